@@ -2,6 +2,7 @@ package ntfs
 
 import (
 	"github.com/gentlemanautomaton/ntfs/attrtype"
+	"github.com/gentlemanautomaton/ntfs/datarun"
 )
 
 // Attribute holds file attribute data.
@@ -11,6 +12,28 @@ type Attribute struct {
 	Nonresident   NonresidentAttributeRecordHeader // When in non-resident form
 	Name          string
 	ResidentValue []byte
+	MappingPairs  []byte // Encoded data runs when in non-resident form
+}
+
+// DataRuns decodes the mapping pairs of non-resident attributes.
+//
+// If the attribute is resident a nil slice will be returned.
+func (attr *Attribute) DataRuns() ([]datarun.MappingPair, error) {
+	if attr.Header.Resident() {
+		return nil, nil
+	}
+	return datarun.Decode(attr.MappingPairs)
+}
+
+// Runlist decodes the mapping pairs of non-resident attributes into a
+// runlist that covers the virtual clusters from LowestVCN to HighestVCN.
+//
+// If the attribute is resident a nil runlist will be returned.
+func (attr *Attribute) Runlist() (datarun.Runlist, error) {
+	if attr.Header.Resident() {
+		return nil, nil
+	}
+	return datarun.DecodeRunlist(attr.MappingPairs, uint64(attr.Nonresident.LowestVCN), uint64(attr.Nonresident.HighestVCN))
 }
 
 // ResidentValueString returns the value of resident attributes as a string.
@@ -110,5 +133,15 @@ func (attr *Attribute) UnmarshalBinary(data []byte) error {
 		copy(attr.ResidentValue, data[start:end])
 	}
 
+	// Read the mapping pairs if it's non-resident
+	if !attr.Header.Resident() {
+		start := int(attr.Nonresident.MappingPairsOffset)
+		if start > len(data) {
+			return ErrMappingPairsOutOfBounds
+		}
+		attr.MappingPairs = make([]byte, len(data)-start)
+		copy(attr.MappingPairs, data[start:])
+	}
+
 	return nil
 }
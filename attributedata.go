@@ -0,0 +1,223 @@
+package ntfs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/gentlemanautomaton/ntfs/attrflag"
+	"github.com/gentlemanautomaton/ntfs/attrtype"
+	"github.com/gentlemanautomaton/ntfs/datarun"
+)
+
+// attributeReader reads the data of a non-resident attribute from the
+// clusters of a volume.
+type attributeReader struct {
+	volume      *Reader
+	r           io.ReadSeeker
+	clusterSize int64
+	runlist     datarun.Runlist
+	initialized int64
+}
+
+// ReadAt reads len(p) bytes of attribute data starting at off. Data in
+// sparse runs or beyond the initialized length of the attribute is read
+// as zeros.
+//
+// If the data is stored in a cluster that is recorded as bad, an error
+// wrapping ErrBadCluster is returned.
+func (ar *attributeReader) ReadAt(p []byte, off int64) (n int, err error) {
+	for n < len(p) {
+		pos := off + int64(n)
+		if pos >= ar.initialized {
+			for i := n; i < len(p); i++ {
+				p[i] = 0
+			}
+			return len(p), nil
+		}
+
+		i := ar.runlist.Find(uint64(pos / ar.clusterSize))
+		if i < 0 {
+			return n, ErrUnmappedVCN
+		}
+		e := ar.runlist[i]
+
+		// Determine how much of this extent we can read
+		start := pos - int64(e.VCN)*ar.clusterSize
+		remaining := int64(e.Length)*ar.clusterSize - start
+		if limit := ar.initialized - pos; remaining > limit {
+			remaining = limit
+		}
+		chunk := p[n:]
+		if int64(len(chunk)) > remaining {
+			chunk = chunk[:remaining]
+		}
+
+		if e.Sparse {
+			for i := range chunk {
+				chunk[i] = 0
+			}
+		} else {
+			first := e.LCN + start/ar.clusterSize
+			last := e.LCN + (start+int64(len(chunk))-1)/ar.clusterSize
+			if lcn, bad := ar.volume.badCluster(ClusterRange{LCN: first, Length: last - first + 1}); bad {
+				return n, fmt.Errorf("%w: LCN %d", ErrBadCluster, lcn)
+			}
+			if _, err := ar.r.Seek(e.LCN*ar.clusterSize+start, io.SeekStart); err != nil {
+				return n, err
+			}
+			if _, err := io.ReadFull(ar.r, chunk); err != nil {
+				return n, err
+			}
+		}
+		n += len(chunk)
+	}
+	return n, nil
+}
+
+// OpenAttribute returns a reader for the data of attr.
+//
+// The data of resident attributes is read from the attribute itself. The
+// data of non-resident attributes is read from the volume according to
+// the attribute's data runs. Compressed attributes are not supported.
+//
+// Only the virtual clusters mapped by attr itself can be read. Use
+// OpenStream to read attributes that span several file record segments.
+func (r *Reader) OpenAttribute(attr *Attribute) (*io.SectionReader, error) {
+	return r.openSegments([]Attribute{*attr})
+}
+
+// OpenStream returns a reader for the data of the attribute of file with
+// the given type code and name. All of the attribute's segments are
+// located, including those stored in extension records.
+func (r *Reader) OpenStream(file *File, code attrtype.Code, name string) (*io.SectionReader, error) {
+	segments, err := r.Segments(file, code, name)
+	if err != nil {
+		return nil, err
+	}
+	return r.openSegments(segments)
+}
+
+// openSegments returns a reader for the data of an attribute made up of
+// one or more segments ordered by their lowest virtual cluster number.
+func (r *Reader) openSegments(segments []Attribute) (*io.SectionReader, error) {
+	first := &segments[0]
+	if first.Header.Resident() {
+		return io.NewSectionReader(bytes.NewReader(first.ResidentValue), 0, int64(len(first.ResidentValue))), nil
+	}
+	if r.Standalone() {
+		return nil, ErrNoVolume
+	}
+	if first.Header.Flags&attrflag.CompressionMask != 0 {
+		return nil, ErrCompressedAttribute
+	}
+	runlist, err := segmentRunlist(segments)
+	if err != nil {
+		return nil, err
+	}
+	ar := &attributeReader{
+		volume:      r,
+		r:           r.r,
+		clusterSize: int64(r.boot.ClusterSize()),
+		runlist:     runlist,
+		initialized: first.Nonresident.InitializedLength,
+	}
+	return io.NewSectionReader(ar, 0, first.Nonresident.DataLength), nil
+}
+
+// Segments returns every segment of the attribute of file with the given
+// type code and name, ordered by their lowest virtual cluster number.
+//
+// Attributes that are too large to fit in a single file record segment are
+// split into segments that are stored in extension records. Those segments
+// are located through the file's $ATTRIBUTE_LIST attribute.
+func (r *Reader) Segments(file *File, code attrtype.Code, name string) ([]Attribute, error) {
+	entries, err := r.AttributeList(file)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		attr, ok := file.NamedAttribute(code, name)
+		if !ok {
+			return nil, ErrAttributeMissing
+		}
+		return []Attribute{*attr}, nil
+	}
+
+	var (
+		segments []Attribute
+		holders  = map[int64]*File{file.ID: file}
+	)
+	for _, entry := range entries {
+		if entry.TypeCode != code || entry.AttributeName != name {
+			continue
+		}
+		id := entry.SegmentReference.SegmentNumber()
+		holder, ok := holders[id]
+		if !ok {
+			if holder, err = r.File(id); err != nil {
+				return nil, err
+			}
+			holders[id] = holder
+		}
+		attr, ok := holder.instance(code, entry.Instance)
+		if !ok {
+			return nil, fmt.Errorf("unable to locate %s attribute instance %d in file record %d: %v", code, entry.Instance, id, ErrAttributeMissing)
+		}
+		segments = append(segments, *attr)
+	}
+	if len(segments) == 0 {
+		return nil, ErrAttributeMissing
+	}
+
+	sort.SliceStable(segments, func(i, j int) bool {
+		return segments[i].Nonresident.LowestVCN < segments[j].Nonresident.LowestVCN
+	})
+	return segments, nil
+}
+
+// AttributeList returns the entries of the $ATTRIBUTE_LIST attribute of
+// file. If the file doesn't have an attribute list a nil slice is returned.
+func (r *Reader) AttributeList(file *File) ([]AttributeListEntry, error) {
+	listAttr, ok := file.Attribute(attrtype.AttributeList)
+	if !ok {
+		return nil, nil
+	}
+	data, err := r.ReadAttribute(listAttr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read attribute list of file record %d: %v", file.ID, err)
+	}
+	entries, err := unmarshalAttributeList(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse attribute list of file record %d: %v", file.ID, err)
+	}
+	return entries, nil
+}
+
+// segmentRunlist merges the runlists of the non-resident segments of an
+// attribute.
+func segmentRunlist(segments []Attribute) (datarun.Runlist, error) {
+	lists := make([]datarun.Runlist, 0, len(segments))
+	for i := range segments {
+		rl, err := segments[i].Runlist()
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, rl)
+	}
+	return datarun.Merge(lists...)
+}
+
+// ReadAttribute reads the entire data of attr into memory.
+func (r *Reader) ReadAttribute(attr *Attribute) ([]byte, error) {
+	sr, err := r.OpenAttribute(attr)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, sr.Size())
+	if _, err := sr.ReadAt(data, 0); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return data, nil
+}
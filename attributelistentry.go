@@ -1,15 +1,75 @@
 package ntfs
 
-import "github.com/gentlemanautomaton/ntfs/attrtype"
+import (
+	"encoding/binary"
+
+	"github.com/gentlemanautomaton/ntfs/attrtype"
+)
+
+// AttributeListEntryMinLength is the minimum length of an attribute list
+// entry in bytes.
+const AttributeListEntryMinLength = 26
 
 // AttributeListEntry stores an entry in an attribute list.
+//
+// https://msdn.microsoft.com/library/bb470037
 type AttributeListEntry struct {
-	TypeCode            attrtype.Code
-	RecordLength        uint16
-	AttributeNameLength uint16
-	AttributeNameOffset uint16
-	LowestVCN           VCN
-	SegmentReference    SegmentReference
-	_                   uint16    // Reserved
-	AttributeName       [1]uint16 // UTF-16 array of varying length
+	TypeCode            attrtype.Code    //  0:4
+	RecordLength        uint16           //  4:6
+	AttributeNameLength uint8            //  6:7 In characters
+	AttributeNameOffset uint8            //  7:8
+	LowestVCN           VCN              //  8:16
+	SegmentReference    SegmentReference // 16:24 The file record segment holding the attribute
+	Instance            uint16           // 24:26
+	AttributeName       string
+}
+
+// UnmarshalBinary unmarshals the little-endian binary representation
+// of an attribute list entry into entry.
+//
+// The provided data must be at least 26 bytes long.
+func (entry *AttributeListEntry) UnmarshalBinary(data []byte) error {
+	if len(data) < AttributeListEntryMinLength {
+		return ErrTruncatedData
+	}
+	entry.TypeCode = attrtype.Unmarshal(data[0:4])
+	entry.RecordLength = binary.LittleEndian.Uint16(data[4:6])
+	entry.AttributeNameLength = data[6]
+	entry.AttributeNameOffset = data[7]
+	entry.LowestVCN = VCN(binary.LittleEndian.Uint64(data[8:16]))
+	if err := entry.SegmentReference.UnmarshalBinary(data[16:24]); err != nil {
+		return err
+	}
+	entry.Instance = binary.LittleEndian.Uint16(data[24:26])
+	entry.AttributeName = ""
+	if entry.AttributeNameLength > 0 {
+		start := int(entry.AttributeNameOffset)
+		end := start + int(entry.AttributeNameLength)*2
+		if end > len(data) {
+			return ErrAttributeNameOutOfBounds
+		}
+		var err error
+		if entry.AttributeName, err = utf16ToString(data[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unmarshalAttributeList unmarshals the entries of an $ATTRIBUTE_LIST
+// attribute.
+func unmarshalAttributeList(data []byte) ([]AttributeListEntry, error) {
+	var entries []AttributeListEntry
+	for pos := 0; pos < len(data); {
+		var entry AttributeListEntry
+		if err := entry.UnmarshalBinary(data[pos:]); err != nil {
+			return entries, err
+		}
+		if entry.RecordLength == 0 {
+			return entries, ErrAttributeLengthInvalid
+		}
+		entries = append(entries, entry)
+		pos += int(entry.RecordLength)
+	}
+	return entries, nil
 }
@@ -63,6 +63,20 @@ func (header *AttributeRecordHeader) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// MarshalBinary returns the 16-byte little-endian binary representation of
+// header.
+func (header *AttributeRecordHeader) MarshalBinary() ([]byte, error) {
+	data := make([]byte, AttributeRecordHeaderLength)
+	binary.LittleEndian.PutUint32(data[0:4], uint32(header.TypeCode))
+	binary.LittleEndian.PutUint32(data[4:8], header.RecordLength)
+	data[8] = uint8(header.FormCode)
+	data[9] = header.NameLength
+	binary.LittleEndian.PutUint16(data[10:12], header.NameOffset)
+	binary.LittleEndian.PutUint16(data[12:14], uint16(header.Flags))
+	binary.LittleEndian.PutUint16(data[14:16], header.Instance)
+	return data, nil
+}
+
 // ResidentAttributeRecordHeaderLength is the length of the resident
 // portion of an attribute header in bytes.
 const ResidentAttributeRecordHeaderLength = 8
@@ -90,10 +104,26 @@ func (header *ResidentAttributeRecordHeader) UnmarshalBinary(data []byte) error
 	return nil
 }
 
+// MarshalBinary returns the 8-byte little-endian binary representation of
+// header.
+func (header *ResidentAttributeRecordHeader) MarshalBinary() ([]byte, error) {
+	data := make([]byte, ResidentAttributeRecordHeaderLength)
+	binary.LittleEndian.PutUint32(data[0:4], header.ValueLength)
+	binary.LittleEndian.PutUint16(data[4:6], header.ValueOffset)
+	data[6] = header.reserved[0]
+	data[7] = header.reserved[1]
+	return data, nil
+}
+
 // NonresidentAttributeRecordHeaderLength is the length of the non-resident
 // portion of an attribute header in bytes.
 const NonresidentAttributeRecordHeaderLength = 48
 
+// CompressedAttributeRecordHeaderLength is the length of the non-resident
+// portion of an attribute header in bytes when the attribute is compressed.
+// It includes the CompressedLength field.
+const CompressedAttributeRecordHeaderLength = 56
+
 // NonresidentAttributeRecordHeader holds information about the non-resident
 //  form of an attribute record.
 type NonresidentAttributeRecordHeader struct {
@@ -105,13 +135,14 @@ type NonresidentAttributeRecordHeader struct {
 	AllocatedLength    int64   // Total bytes allocated
 	DataLength         int64   // Total bytes of actual data (the "file size")
 	InitializedLength  int64   // Total bytes initialized
-	CompressedLength   int64   // Total bytes compressed
+	CompressedLength   int64   // Total bytes compressed, only present when CompressionUnit is non-zero
 }
 
 // UnmarshalBinary unmarshals the little-endian binary representation
 // of the non-resident portion of an attribute record header into header.
 //
-// The provided data must be at least 8 bytes long.
+// The provided data must be at least 48 bytes long, or 56 bytes long if
+// the attribute is compressed.
 func (header *NonresidentAttributeRecordHeader) UnmarshalBinary(data []byte) error {
 	if len(data) < NonresidentAttributeRecordHeaderLength {
 		return ErrTruncatedData
@@ -127,6 +158,34 @@ func (header *NonresidentAttributeRecordHeader) UnmarshalBinary(data []byte) err
 	header.AllocatedLength = int64(binary.LittleEndian.Uint64(data[24:32]))
 	header.DataLength = int64(binary.LittleEndian.Uint64(data[32:40]))
 	header.InitializedLength = int64(binary.LittleEndian.Uint64(data[40:48]))
-	//header.CompressedLength = int64(binary.LittleEndian.Uint64(data[48:56]))
+	header.CompressedLength = 0
+	if header.CompressionUnit != 0 {
+		if len(data) < CompressedAttributeRecordHeaderLength {
+			return ErrTruncatedData
+		}
+		header.CompressedLength = int64(binary.LittleEndian.Uint64(data[48:56]))
+	}
 	return nil
 }
+
+// MarshalBinary returns the little-endian binary representation of header.
+// It is 48 bytes long, or 56 bytes long if CompressionUnit is non-zero.
+func (header *NonresidentAttributeRecordHeader) MarshalBinary() ([]byte, error) {
+	length := NonresidentAttributeRecordHeaderLength
+	if header.CompressionUnit != 0 {
+		length = CompressedAttributeRecordHeaderLength
+	}
+	data := make([]byte, length)
+	binary.LittleEndian.PutUint64(data[0:8], uint64(header.LowestVCN))
+	binary.LittleEndian.PutUint64(data[8:16], uint64(header.HighestVCN))
+	binary.LittleEndian.PutUint16(data[16:18], header.MappingPairsOffset)
+	binary.LittleEndian.PutUint16(data[18:20], header.CompressionUnit)
+	copy(data[20:24], header.reserved[:])
+	binary.LittleEndian.PutUint64(data[24:32], uint64(header.AllocatedLength))
+	binary.LittleEndian.PutUint64(data[32:40], uint64(header.DataLength))
+	binary.LittleEndian.PutUint64(data[40:48], uint64(header.InitializedLength))
+	if header.CompressionUnit != 0 {
+		binary.LittleEndian.PutUint64(data[48:56], uint64(header.CompressedLength))
+	}
+	return data, nil
+}
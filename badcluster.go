@@ -0,0 +1,119 @@
+package ntfs
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gentlemanautomaton/ntfs/attrtype"
+)
+
+// https://flatcap.org/linux-ntfs/ntfs/files/badclus.html
+
+// BadClusterStream is the name of the $DATA stream of the $BadClus system
+// file. It is a sparse stream the size of the volume in which each bad
+// cluster is mapped to itself.
+const BadClusterStream = "$Bad"
+
+// ClusterRange is a range of logical clusters.
+type ClusterRange struct {
+	LCN    int64
+	Length int64 // In clusters
+}
+
+// End returns the logical cluster number that follows the range.
+func (cr ClusterRange) End() int64 {
+	return cr.LCN + cr.Length
+}
+
+// Overlaps returns true if cr and other have any clusters in common.
+func (cr ClusterRange) Overlaps(other ClusterRange) bool {
+	return cr.LCN < other.End() && other.LCN < cr.End()
+}
+
+// String returns a description of the cluster range.
+func (cr ClusterRange) String() string {
+	return fmt.Sprintf("LCN %d+%d", cr.LCN, cr.Length)
+}
+
+// BadClusters returns the ranges of clusters that are recorded as bad in
+// the $BadClus system file, ordered by logical cluster number.
+//
+// The list is read when the reader is created.
+func (r *Reader) BadClusters() []ClusterRange {
+	return append([]ClusterRange(nil), r.bad...)
+}
+
+// BadClusterFile describes a stream with data runs that intersect bad
+// clusters.
+type BadClusterFile struct {
+	ClusterMapping              // The portion of the stream that is stored in bad clusters
+	Bad            ClusterRange // The bad cluster range that it intersects
+}
+
+// ScanBadClusters returns the portion of each stream on the volume that is
+// stored in clusters recorded as bad. The $BadClus system file itself is
+// excluded. Standalone readers return ErrNoVolume.
+func (r *Reader) ScanBadClusters() ([]BadClusterFile, error) {
+	if r.Standalone() {
+		return nil, ErrNoVolume
+	}
+	if len(r.bad) == 0 {
+		return nil, nil
+	}
+	idx, err := r.ClusterIndex()
+	if err != nil {
+		return nil, err
+	}
+	var files []BadClusterFile
+	for _, bad := range r.bad {
+		for _, m := range idx.Range(bad.LCN, bad.Length) {
+			if m.File.SegmentNumber() == RecordBadClus {
+				continue
+			}
+			files = append(files, BadClusterFile{
+				ClusterMapping: m,
+				Bad:            bad,
+			})
+		}
+	}
+	return files, nil
+}
+
+// loadBadClusters reads the list of bad clusters from the $BadClus system
+// file.
+func (r *Reader) loadBadClusters() error {
+	file, err := r.File(RecordBadClus)
+	if err != nil {
+		return err
+	}
+	extents, err := r.Extents(file, attrtype.Data, BadClusterStream)
+	if err != nil {
+		return err
+	}
+	var bad []ClusterRange
+	for _, e := range extents {
+		if !e.Sparse {
+			bad = append(bad, ClusterRange{LCN: e.LCN, Length: e.Clusters})
+		}
+	}
+	sort.Slice(bad, func(i, j int) bool {
+		return bad[i].LCN < bad[j].LCN
+	})
+	r.bad = bad
+	return nil
+}
+
+// badCluster returns the first bad cluster within cr. If cr doesn't
+// include any bad clusters ok will be false.
+func (r *Reader) badCluster(cr ClusterRange) (lcn int64, ok bool) {
+	i := sort.Search(len(r.bad), func(i int) bool {
+		return r.bad[i].End() > cr.LCN
+	})
+	if i < len(r.bad) && r.bad[i].Overlaps(cr) {
+		if r.bad[i].LCN > cr.LCN {
+			return r.bad[i].LCN, true
+		}
+		return cr.LCN, true
+	}
+	return 0, false
+}
@@ -15,6 +15,20 @@ func (bitmap *Bitmap) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// Bit returns true if bit i of the bitmap is set. Bits beyond the end of
+// the bitmap are not set.
+func (bitmap Bitmap) Bit(i int64) bool {
+	if i < 0 || i/8 >= int64(len(bitmap)) {
+		return false
+	}
+	return bitmap[i/8]&(1<<uint(i%8)) != 0
+}
+
+// set sets bit i of the bitmap.
+func (bitmap Bitmap) set(i int64) {
+	bitmap[i/8] |= 1 << uint(i%8)
+}
+
 // String returns a description of the bitmap.
 func (bitmap Bitmap) String() string {
 	if len(bitmap) == 0 {
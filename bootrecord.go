@@ -47,3 +47,13 @@ func (boot *BootRecord) UnmarshalBinary(data []byte) error {
 	copy(boot.OEMID[:], data[3:11])
 	return boot.ParameterBlock.UnmarshalBinary(data[11:])
 }
+
+// MarshalBinary returns the 84-byte little-endian binary representation of
+// boot.
+func (boot *BootRecord) MarshalBinary() ([]byte, error) {
+	data := make([]byte, BootRecordLength)
+	copy(data[0:3], boot.JumpInstruction[:])
+	copy(data[3:11], boot.OEMID[:])
+	boot.ParameterBlock.marshal(data[11:])
+	return data, nil
+}
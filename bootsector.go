@@ -0,0 +1,85 @@
+package ntfs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+// https://flatcap.org/linux-ntfs/ntfs/files/boot.html
+
+// BootSectorLength is the length of an NTFS boot sector in bytes. NTFS
+// keeps a copy of the boot sector in the last sector of the volume.
+const BootSectorLength = 512
+
+// bootSectorChecksumOffset is the offset of the checksum within the boot
+// sector.
+const bootSectorChecksumOffset = 11 + 69
+
+// BootSectorSignature is the end-of-sector marker present in the last two
+// bytes of a valid boot sector.
+var BootSectorSignature = [2]byte{0x55, 0xAA}
+
+// BootSector holds the raw data of an NTFS boot sector, including its volume
+// boot record, boot code and end-of-sector marker.
+type BootSector [BootSectorLength]byte
+
+// ReadFrom reads 512 bytes of boot sector data from r into sector.
+func (sector *BootSector) ReadFrom(r io.Reader) (n int64, err error) {
+	n32, err := io.ReadFull(r, sector[:])
+	n = int64(n32)
+	if err == io.ErrUnexpectedEOF || err == io.EOF {
+		return n, ErrTruncatedData
+	}
+	return n, err
+}
+
+// Signature returns the end-of-sector marker of the boot sector.
+func (sector *BootSector) Signature() [2]byte {
+	return [2]byte{sector[510], sector[511]}
+}
+
+// Checksum computes the checksum of the boot sector. It is the sum of the
+// little-endian 32-bit words that precede the checksum field.
+func (sector *BootSector) Checksum() uint32 {
+	var sum uint32
+	for i := 0; i < bootSectorChecksumOffset; i += 4 {
+		sum += binary.LittleEndian.Uint32(sector[i : i+4])
+	}
+	return sum
+}
+
+// Record unmarshals the volume boot record contained in the boot sector.
+func (sector *BootSector) Record() (boot BootRecord, err error) {
+	err = boot.UnmarshalBinary(sector[:])
+	return
+}
+
+// Validate returns a non-nil error if the boot sector does not hold a
+// valid NTFS volume boot record.
+//
+// It verifies the file system label, the end-of-sector marker, the
+// geometry described by the parameter block and the checksum. Windows
+// typically leaves the checksum set to zero, in which case it is not
+// verified.
+func (sector *BootSector) Validate() error {
+	boot, err := sector.Record()
+	if err != nil {
+		return err
+	}
+	if sector.Signature() != BootSectorSignature {
+		return ErrInvalidBootSignature
+	}
+	if err := boot.ParameterBlock.Validate(); err != nil {
+		return err
+	}
+	if boot.Checksum != 0 && boot.Checksum != sector.Checksum() {
+		return ErrInvalidChecksum
+	}
+	return nil
+}
+
+// Equal returns true if sector and other hold identical data.
+func (sector *BootSector) Equal(other *BootSector) bool {
+	return bytes.Equal(sector[:], other[:])
+}
@@ -0,0 +1,412 @@
+package ntfs
+
+import (
+	"fmt"
+
+	"github.com/gentlemanautomaton/ntfs/attrtype"
+)
+
+// InconsistencyKind identifies a kind of file system inconsistency.
+type InconsistencyKind string
+
+// Kinds of file system inconsistencies.
+const (
+	UnreadableRecord       InconsistencyKind = "unreadable-record"        // An in-use file record can't be parsed
+	RecordBitmapMismatch   InconsistencyKind = "record-bitmap-mismatch"   // A record's in-use flag disagrees with the $MFT bitmap
+	InvalidBaseRecord      InconsistencyKind = "invalid-base-record"      // An extension record refers to an invalid base record
+	InvalidDataRuns        InconsistencyKind = "invalid-data-runs"        // An attribute's data runs can't be decoded
+	ClustersOutOfRange     InconsistencyKind = "clusters-out-of-range"    // A data run refers to clusters beyond the volume
+	CrossLinkedClusters    InconsistencyKind = "cross-linked-clusters"    // Clusters are claimed by more than one data run
+	OrphanedClusters       InconsistencyKind = "orphaned-clusters"        // Clusters are allocated in $Bitmap but not claimed
+	UnallocatedClusters    InconsistencyKind = "unallocated-clusters"     // Clusters are claimed but free in $Bitmap
+	UnreadableIndex        InconsistencyKind = "unreadable-index"         // A directory's file name index can't be read
+	DanglingIndexEntry     InconsistencyKind = "dangling-index-entry"     // An index entry refers to a record that isn't in use
+	IndexSequenceMismatch  InconsistencyKind = "index-sequence-mismatch"  // An index entry refers to a reused record
+	IndexParentMismatch    InconsistencyKind = "index-parent-mismatch"    // An index entry's key names a different parent
+	InvalidParent          InconsistencyKind = "invalid-parent"           // A file name refers to a parent that isn't an in-use directory
+	ParentSequenceMismatch InconsistencyKind = "parent-sequence-mismatch" // A file name refers to a reused parent record
+	LinkCountMismatch      InconsistencyKind = "link-count-mismatch"      // A record's link count disagrees with its file names
+	MisplacedRecord        InconsistencyKind = "misplaced-record"         // A record's header names a different record number
+)
+
+// Inconsistency describes a problem found by a consistency check.
+type Inconsistency struct {
+	Kind   InconsistencyKind `json:"kind"`
+	Record int64             `json:"record"` // The file record number, or -1
+	LCN    int64             `json:"lcn"`    // The first logical cluster number, or -1
+	Length int64             `json:"length"` // The number of clusters, or 0
+	Detail string            `json:"detail"`
+}
+
+// String returns a description of the inconsistency.
+func (issue Inconsistency) String() string {
+	output := string(issue.Kind)
+	if issue.Record >= 0 {
+		output += fmt.Sprintf(" record %d", issue.Record)
+	}
+	if issue.LCN >= 0 {
+		output += fmt.Sprintf(" LCN %d+%d", issue.LCN, issue.Length)
+	}
+	if issue.Detail != "" {
+		output += ": " + issue.Detail
+	}
+	return output
+}
+
+// checkRecord holds the state of a file record that is needed to cross-check
+// it against other records.
+type checkRecord struct {
+	inUse     bool
+	directory bool
+	sequence  uint16
+	links     uint16        // The hard link count of base records
+	names     uint16        // The number of $FILE_NAME attributes of base records
+	base      FileReference // The base record of extension records
+}
+
+// checkIndexEntry is an entry in the file name index of a directory.
+type checkIndexEntry struct {
+	directory int64
+	target    FileReference
+	parent    int64
+	name      string
+}
+
+// checkRun is a data run of a non-resident attribute that lies within the
+// volume.
+type checkRun struct {
+	record int64
+	lcn    int64
+	length int64
+	attr   string
+}
+
+// checkParent is a parent reference from a $FILE_NAME attribute.
+type checkParent struct {
+	record int64
+	parent FileReference
+	name   string
+}
+
+// checker holds the state of a consistency check.
+type checker struct {
+	r        *Reader
+	report   func(Inconsistency)
+	records  []checkRecord
+	parents  []checkParent
+	entries  []checkIndexEntry
+	runs     []checkRun
+	clusters int64
+	claimed  Bitmap // Clusters claimed by the data runs of in-use records
+	crossed  Bitmap // Clusters claimed more than once
+	crossing bool
+}
+
+// Check performs an offline consistency check of the volume without
+// modifying it. Each inconsistency that is found is passed to report.
+//
+// The cluster bitmap is compared with the data runs of every in-use file
+// record to find cross-linked, orphaned and unallocated clusters. The $MFT
+// bitmap is compared with the in-use flag of every record. Directory index
+// entries, parent references, hard link counts and sequence numbers are
+// cross-checked against the records they refer to.
+//
+// An error is returned only if the check cannot proceed. If the number of
+// records in the master file table is unknown ErrMFTDataUnavailable is
+// returned. Standalone readers return ErrNoVolume.
+func (r *Reader) Check(report func(Inconsistency)) error {
+	if r.Standalone() {
+		return ErrNoVolume
+	}
+	if r.records == 0 {
+		return ErrMFTDataUnavailable
+	}
+	clusterBitmap, err := r.ClusterBitmap()
+	if err != nil {
+		return fmt.Errorf("unable to read the cluster bitmap: %v", err)
+	}
+	mftBitmap, err := r.MFTBitmap()
+	if err != nil {
+		return fmt.Errorf("unable to read the $MFT bitmap: %v", err)
+	}
+
+	c := checker{
+		r:        r,
+		report:   report,
+		records:  make([]checkRecord, r.RecordCount()),
+		clusters: r.boot.Clusters(),
+	}
+	c.claimed = make(Bitmap, (c.clusters+7)/8)
+	c.crossed = make(Bitmap, (c.clusters+7)/8)
+
+	c.checkRecords(mftBitmap)
+	c.checkBaseRecords()
+	c.checkLinks()
+	c.checkParents()
+	c.checkIndexes()
+	c.checkClusters(clusterBitmap)
+	if c.crossing {
+		c.checkCrossLinks()
+	}
+
+	return nil
+}
+
+// checkRecords reads every file record, compares its in-use flag with the
+// $MFT bitmap and claims the clusters of its data runs. The state needed by
+// the later passes is collected so that each record is only read once.
+func (c *checker) checkRecords(mftBitmap Bitmap) {
+	for id := range c.records {
+		id := int64(id)
+		allocated := mftBitmap.Bit(id)
+		file, err := c.r.File(id)
+		if err != nil {
+			if allocated {
+				c.issue(UnreadableRecord, id, -1, 0, err.Error())
+			}
+			continue
+		}
+
+		header := &file.Header
+		if header.InUse() != allocated {
+			c.issue(RecordBitmapMismatch, id, -1, 0, fmt.Sprintf("in-use flag %t, bitmap %t", header.InUse(), allocated))
+		}
+		if !header.InUse() {
+			continue
+		}
+		if header.Extended() && int64(header.RecordNumber) != id&0xffffffff {
+			c.issue(MisplacedRecord, id, -1, 0, fmt.Sprintf("header names record %d", header.RecordNumber))
+		}
+
+		c.records[id] = checkRecord{
+			inUse:     true,
+			directory: header.Directory(),
+			sequence:  header.SequenceNumber,
+			links:     header.HardLinkCount,
+			base:      header.BaseFileRecordSegment,
+		}
+		if header.Directory() {
+			c.addIndexEntries(id, file)
+		}
+
+		for a := range file.Attributes {
+			attr := &file.Attributes[a]
+			if attr.Header.Resident() {
+				if attr.Header.TypeCode == attrtype.FileName {
+					c.addName(id, file, attr)
+				}
+				continue
+			}
+			c.claim(id, attr)
+		}
+	}
+}
+
+// addName records a $FILE_NAME attribute of a base or extension record.
+func (c *checker) addName(id int64, file *File, attr *Attribute) {
+	var fn FileName
+	if err := fn.UnmarshalBinary(attr.ResidentValue); err != nil {
+		c.issue(UnreadableRecord, id, -1, 0, fmt.Sprintf("unable to parse file name: %v", err))
+		return
+	}
+	base := id
+	if !file.Header.BaseFileRecordSegment.IsZero() {
+		base = file.Header.BaseFileRecordSegment.SegmentNumber()
+	}
+	if base >= 0 && base < int64(len(c.records)) {
+		c.records[base].names++
+	}
+	c.parents = append(c.parents, checkParent{
+		record: base,
+		parent: fn.ParentDirectory,
+		name:   fn.Value,
+	})
+}
+
+// claim marks the clusters of a non-resident attribute as claimed.
+func (c *checker) claim(id int64, attr *Attribute) {
+	runlist, err := attr.Runlist()
+	if err != nil {
+		c.issue(InvalidDataRuns, id, -1, 0, fmt.Sprintf("%s %q: %v", attr.Header.TypeCode, attr.Name, err))
+		return
+	}
+	desc := fmt.Sprintf("%s %q", attr.Header.TypeCode, attr.Name)
+	for _, e := range runlist {
+		if e.Sparse {
+			continue
+		}
+		length := int64(e.Length)
+		if e.LCN < 0 || e.LCN+length > c.clusters {
+			c.issue(ClustersOutOfRange, id, e.LCN, length, desc)
+			continue
+		}
+		c.runs = append(c.runs, checkRun{record: id, lcn: e.LCN, length: length, attr: desc})
+		for lcn := e.LCN; lcn < e.LCN+length; lcn++ {
+			if c.claimed.Bit(lcn) {
+				c.crossed.set(lcn)
+				c.crossing = true
+			} else {
+				c.claimed.set(lcn)
+			}
+		}
+	}
+}
+
+// checkBaseRecords verifies that every extension record refers to an
+// in-use base record with a matching sequence number.
+func (c *checker) checkBaseRecords() {
+	for id := range c.records {
+		id := int64(id)
+		if !c.records[id].inUse {
+			continue
+		}
+		ref := c.records[id].base
+		if ref.IsZero() {
+			continue
+		}
+		base := ref.SegmentNumber()
+		switch {
+		case base >= int64(len(c.records)) || !c.records[base].inUse:
+			c.issue(InvalidBaseRecord, id, -1, 0, fmt.Sprintf("base record %d is not in use", base))
+		case c.records[base].sequence != ref.SequenceNumber:
+			c.issue(InvalidBaseRecord, id, -1, 0, fmt.Sprintf("base record %d has sequence %d, expected %d", base, c.records[base].sequence, ref.SequenceNumber))
+		}
+	}
+}
+
+// checkLinks compares the hard link count of each base record with the
+// number of $FILE_NAME attributes that belong to it.
+func (c *checker) checkLinks() {
+	for id := range c.records {
+		rec := &c.records[id]
+		if !rec.inUse || rec.names == 0 {
+			continue
+		}
+		if rec.links != rec.names {
+			c.issue(LinkCountMismatch, int64(id), -1, 0, fmt.Sprintf("link count %d, file names %d", rec.links, rec.names))
+		}
+	}
+}
+
+// checkParents verifies that every file name refers to an in-use directory
+// with a matching sequence number.
+func (c *checker) checkParents() {
+	for _, p := range c.parents {
+		parent := p.parent.SegmentNumber()
+		switch {
+		case parent >= int64(len(c.records)) || !c.records[parent].inUse:
+			c.issue(InvalidParent, p.record, -1, 0, fmt.Sprintf("%q: parent record %d is not in use", p.name, parent))
+		case !c.records[parent].directory:
+			c.issue(InvalidParent, p.record, -1, 0, fmt.Sprintf("%q: parent record %d is not a directory", p.name, parent))
+		case c.records[parent].sequence != p.parent.SequenceNumber:
+			c.issue(ParentSequenceMismatch, p.record, -1, 0, fmt.Sprintf("%q: parent record %d has sequence %d, expected %d", p.name, parent, c.records[parent].sequence, p.parent.SequenceNumber))
+		}
+	}
+}
+
+// addIndexEntries records the file name index entries of a directory.
+func (c *checker) addIndexEntries(id int64, file *File) {
+	if _, ok := file.NamedAttribute(attrtype.IndexRoot, FileNameIndex); !ok {
+		return
+	}
+	entries, err := c.r.IndexEntries(file, FileNameIndex)
+	if err != nil {
+		c.issue(UnreadableIndex, id, -1, 0, err.Error())
+	}
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Last() {
+			continue
+		}
+		fn, err := entry.FileName()
+		if err != nil {
+			c.issue(UnreadableIndex, id, -1, 0, fmt.Sprintf("unable to parse index entry key: %v", err))
+			continue
+		}
+		c.entries = append(c.entries, checkIndexEntry{
+			directory: id,
+			target:    entry.FileReference,
+			parent:    fn.ParentDirectory.SegmentNumber(),
+			name:      fn.Value,
+		})
+	}
+}
+
+// checkIndexes verifies that every file name index entry of every directory
+// refers to an in-use record with a matching sequence number.
+func (c *checker) checkIndexes() {
+	for _, entry := range c.entries {
+		id, target := entry.directory, entry.target.SegmentNumber()
+		switch {
+		case target >= int64(len(c.records)) || !c.records[target].inUse:
+			c.issue(DanglingIndexEntry, id, -1, 0, fmt.Sprintf("%q: record %d is not in use", entry.name, target))
+		case c.records[target].sequence != entry.target.SequenceNumber:
+			c.issue(IndexSequenceMismatch, id, -1, 0, fmt.Sprintf("%q: record %d has sequence %d, expected %d", entry.name, target, c.records[target].sequence, entry.target.SequenceNumber))
+		case entry.parent != id:
+			c.issue(IndexParentMismatch, id, -1, 0, fmt.Sprintf("%q: key names parent record %d", entry.name, entry.parent))
+		}
+	}
+}
+
+// checkClusters compares the claimed clusters with the cluster bitmap and
+// reports each range of clusters that disagree.
+func (c *checker) checkClusters(clusterBitmap Bitmap) {
+	var (
+		start int64 = -1
+		kind  InconsistencyKind
+	)
+	flush := func(end int64) {
+		if start >= 0 {
+			c.issue(kind, -1, start, end-start, "")
+			start = -1
+		}
+	}
+	for lcn := int64(0); lcn < c.clusters; lcn++ {
+		allocated, claimed := clusterBitmap.Bit(lcn), c.claimed.Bit(lcn)
+		var current InconsistencyKind
+		switch {
+		case allocated && !claimed:
+			current = OrphanedClusters
+		case claimed && !allocated:
+			current = UnallocatedClusters
+		default:
+			flush(lcn)
+			continue
+		}
+		if start >= 0 && current != kind {
+			flush(lcn)
+		}
+		if start < 0 {
+			start, kind = lcn, current
+		}
+	}
+	flush(c.clusters)
+}
+
+// checkCrossLinks reports each data run that includes cross-linked
+// clusters.
+func (c *checker) checkCrossLinks() {
+	for _, run := range c.runs {
+		start := int64(-1)
+		for lcn := run.lcn; lcn <= run.lcn+run.length; lcn++ {
+			crossed := lcn < run.lcn+run.length && c.crossed.Bit(lcn)
+			if crossed && start < 0 {
+				start = lcn
+			} else if !crossed && start >= 0 {
+				c.issue(CrossLinkedClusters, run.record, start, lcn-start, run.attr)
+				start = -1
+			}
+		}
+	}
+}
+
+// issue reports an inconsistency.
+func (c *checker) issue(kind InconsistencyKind, record, lcn, length int64, detail string) {
+	c.report(Inconsistency{
+		Kind:   kind,
+		Record: record,
+		LCN:    lcn,
+		Length: length,
+		Detail: detail,
+	})
+}
@@ -0,0 +1,146 @@
+package ntfs
+
+import (
+	"sort"
+
+	"github.com/gentlemanautomaton/ntfs/attrtype"
+)
+
+// ClusterMapping maps a range of logical clusters to the stream that owns
+// them.
+type ClusterMapping struct {
+	LCN      int64         // The first logical cluster number
+	Length   int64         // In clusters
+	File     FileReference // The base file record of the owner
+	Segment  int64         // The file record segment holding the data run
+	TypeCode attrtype.Code // The type of the owning attribute
+	Name     string        // The name of the owning attribute
+	VCN      VCN           // The virtual cluster number of the first cluster
+	Offset   int64         // The offset of the first cluster within the stream in bytes
+}
+
+// End returns the logical cluster number that follows the mapping.
+func (m *ClusterMapping) End() int64 {
+	return m.LCN + m.Length
+}
+
+// ClusterIndex maps logical clusters back to the streams that own them.
+// Clusters that are cross-linked map to more than one stream.
+type ClusterIndex struct {
+	clusterSize int64
+	mappings    []ClusterMapping // Sorted by LCN
+	maxEnd      []int64          // The greatest end of mappings[0:i+1]
+}
+
+// ClusterIndex builds a cluster index from the data runs of every in-use
+// file record in the master file table. Sparse runs are not included.
+// Standalone readers return ErrNoVolume.
+func (r *Reader) ClusterIndex() (*ClusterIndex, error) {
+	if r.Standalone() {
+		return nil, ErrNoVolume
+	}
+	idx := &ClusterIndex{clusterSize: int64(r.boot.ClusterSize())}
+	err := r.Walk(func(file *File, err error) error {
+		if err != nil || !file.Header.InUse() {
+			return nil
+		}
+		ref := file.Reference()
+		for a := range file.Attributes {
+			attr := &file.Attributes[a]
+			if attr.Header.Resident() {
+				continue
+			}
+			runlist, err := attr.Runlist()
+			if err != nil {
+				continue
+			}
+			for _, e := range runlist {
+				if e.Sparse {
+					continue
+				}
+				idx.mappings = append(idx.mappings, ClusterMapping{
+					LCN:      e.LCN,
+					Length:   int64(e.Length),
+					File:     ref,
+					Segment:  file.ID,
+					TypeCode: attr.Header.TypeCode,
+					Name:     attr.Name,
+					VCN:      VCN(e.VCN),
+					Offset:   int64(e.VCN) * idx.clusterSize,
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(idx.mappings, func(i, j int) bool {
+		return idx.mappings[i].LCN < idx.mappings[j].LCN
+	})
+	idx.maxEnd = make([]int64, len(idx.mappings))
+	var max int64
+	for i := range idx.mappings {
+		if end := idx.mappings[i].End(); end > max {
+			max = end
+		}
+		idx.maxEnd[i] = max
+	}
+
+	return idx, nil
+}
+
+// Len returns the number of mappings in the index.
+func (idx *ClusterIndex) Len() int {
+	return len(idx.mappings)
+}
+
+// Lookup returns the mappings of the streams that own the cluster at lcn,
+// each trimmed to that single cluster. It returns nil if the cluster isn't
+// owned by any stream.
+func (idx *ClusterIndex) Lookup(lcn int64) []ClusterMapping {
+	return idx.Range(lcn, 1)
+}
+
+// Range returns the mappings of the streams that own clusters in the range
+// of length clusters starting at lcn. Each mapping is trimmed to the range.
+// The mappings are ordered by logical cluster number. It returns nil if
+// length is not positive.
+func (idx *ClusterIndex) Range(lcn, length int64) []ClusterMapping {
+	if length <= 0 {
+		return nil
+	}
+	end := lcn + length
+
+	// Find the first mapping that starts at or beyond the end of the range
+	i := sort.Search(len(idx.mappings), func(i int) bool {
+		return idx.mappings[i].LCN >= end
+	})
+
+	// Walk backwards until no earlier mapping can reach the range
+	var result []ClusterMapping
+	for j := i - 1; j >= 0 && idx.maxEnd[j] > lcn; j-- {
+		m := idx.mappings[j]
+		if m.End() <= lcn {
+			continue
+		}
+		if m.LCN < lcn {
+			skip := lcn - m.LCN
+			m.LCN += skip
+			m.Length -= skip
+			m.VCN += VCN(skip)
+			m.Offset += skip * idx.clusterSize
+		}
+		if m.End() > end {
+			m.Length = end - m.LCN
+		}
+		result = append(result, m)
+	}
+
+	// Restore ascending order
+	for a, b := 0, len(result)-1; a < b; a, b = a+1, b-1 {
+		result[a], result[b] = result[b], result[a]
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+// Command ntfsck performs an offline consistency check of an NTFS volume
+// image without modifying it.
+//
+// Each inconsistency is written to standard output as a line of JSON that
+// includes the affected record number and logical cluster numbers. The
+// command exits with status 1 if any inconsistencies are found.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gentlemanautomaton/ntfs"
+)
+
+func main() {
+	offset := flag.Int64("offset", 0, "byte offset of the volume within the image")
+	text := flag.Bool("text", false, "write a human-readable report instead of JSON lines")
+	flag.Parse()
+	path := flag.Arg(0)
+	if path == "" {
+		fmt.Fprintf(os.Stderr, "usage: %s [-offset bytes] [-text] <volume image>\n", os.Args[0])
+		os.Exit(2)
+	}
+
+	// Open the raw file
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to open \"%s\": %s\n", path, err)
+		os.Exit(2)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to stat \"%s\": %s\n", path, err)
+		os.Exit(2)
+	}
+
+	r, err := ntfs.NewReader(io.NewSectionReader(f, *offset, fi.Size()-*offset))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to read NTFS volume: %v\n", err)
+		os.Exit(2)
+	}
+	for _, warning := range r.Warnings() {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", warning)
+	}
+
+	var (
+		enc    = json.NewEncoder(os.Stdout)
+		found  int
+		encErr error
+	)
+	err = r.Check(func(issue ntfs.Inconsistency) {
+		found++
+		if *text {
+			fmt.Println(issue)
+			return
+		}
+		if encErr == nil {
+			encErr = enc.Encode(issue)
+		}
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to check NTFS volume: %v\n", err)
+		os.Exit(2)
+	}
+	if encErr != nil {
+		fmt.Fprintf(os.Stderr, "Unable to write report: %v\n", encErr)
+		os.Exit(2)
+	}
+
+	fmt.Fprintf(os.Stderr, "%d inconsistencies found\n", found)
+	if found > 0 {
+		f.Close()
+		os.Exit(1)
+	}
+}
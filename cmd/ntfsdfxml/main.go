@@ -0,0 +1,86 @@
+// Command ntfsdfxml describes an NTFS volume image and every file record
+// within it in the Digital Forensics XML format.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gentlemanautomaton/ntfs"
+	"github.com/gentlemanautomaton/ntfs/dfxml"
+)
+
+func main() {
+	offset := flag.Int64("offset", 0, "byte offset of the volume within the image")
+	hashes := flag.String("hash", "", "comma-separated hash digests to compute: md5, sha1, sha256")
+	output := flag.String("o", "", "output file (default: standard output)")
+	flag.Parse()
+	path := flag.Arg(0)
+	if path == "" {
+		fmt.Fprintf(os.Stderr, "usage: %s [-offset bytes] [-hash md5,sha1,sha256] [-o file] <volume image>\n", os.Args[0])
+		os.Exit(2)
+	}
+
+	// Open the raw file
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to open \"%s\": %s\n", path, err)
+		os.Exit(2)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to stat \"%s\": %s\n", path, err)
+		os.Exit(2)
+	}
+
+	r, err := ntfs.NewReader(io.NewSectionReader(f, *offset, fi.Size()-*offset))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to read NTFS volume: %v\n", err)
+		os.Exit(2)
+	}
+	for _, warning := range r.Warnings() {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", warning)
+	}
+
+	var skipped int
+	opts := dfxml.Options{
+		Program:       filepath.Base(os.Args[0]),
+		ImageFilename: path,
+		ImageOffset:   *offset,
+		RecordError: func(id int64, err error) {
+			skipped++
+		},
+	}
+	if *hashes != "" {
+		opts.Hashes = strings.Split(*hashes, ",")
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		if out, err = os.Create(*output); err != nil {
+			fmt.Fprintf(os.Stderr, "Unable to create \"%s\": %s\n", *output, err)
+			os.Exit(2)
+		}
+		defer out.Close()
+	}
+	w := bufio.NewWriter(out)
+
+	err = dfxml.Write(w, r, opts)
+	if flushErr := w.Flush(); err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to write DFXML: %v\n", err)
+		os.Exit(2)
+	}
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "%d records could not be read\n", skipped)
+	}
+}
@@ -0,0 +1,87 @@
+// Command ntfsextents prints the extents of a file on an NTFS volume image,
+// similar to FSCTL_GET_RETRIEVAL_POINTERS or filefrag.
+//
+// The file is identified by its path, or by its file record number with the
+// -record flag.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/gentlemanautomaton/ntfs"
+)
+
+func main() {
+	offset := flag.Int64("offset", 0, "byte offset of the volume within the image")
+	stream := flag.String("stream", "", "name of the data stream")
+	record := flag.Int64("record", -1, "file record number of the file, instead of its path")
+	flag.Parse()
+	path, target := flag.Arg(0), flag.Arg(1)
+	if path == "" || (target == "") == (*record < 0) {
+		fmt.Fprintf(os.Stderr, "usage: %s [-offset bytes] [-stream name] <volume image> <path>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s [-offset bytes] [-stream name] -record number <volume image>\n", os.Args[0])
+		os.Exit(2)
+	}
+
+	// Open the raw file
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to open \"%s\": %s\n", path, err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to stat \"%s\": %s\n", path, err)
+		os.Exit(1)
+	}
+
+	r, err := ntfs.NewReader(io.NewSectionReader(f, *offset, fi.Size()-*offset))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to read NTFS volume: %v\n", err)
+		os.Exit(1)
+	}
+
+	var file *ntfs.File
+	if *record >= 0 {
+		file, err = r.File(*record)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Unable to read file record %d: %v\n", *record, err)
+			os.Exit(1)
+		}
+	} else {
+		file, err = r.FileByPath(target)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Unable to find \"%s\": %v\n", target, err)
+			os.Exit(1)
+		}
+	}
+
+	extents, err := file.StreamExtents(*stream)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to read extents of file record %d: %v\n", file.ID, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("File record %d: %d extents found\n", file.ID, len(extents))
+	if len(extents) == 0 {
+		return
+	}
+	fmt.Printf("%5s %16s %16s %12s %16s %10s %s\n", "ext", "logical_offset", "physical_offset", "length", "lcn", "clusters", "flags")
+	for i, e := range extents {
+		lcn, physical := strconv.FormatInt(e.LCN, 10), strconv.FormatInt(e.PhysicalOffset, 10)
+		flags := ""
+		if e.Sparse {
+			lcn, physical, flags = "-", "-", "sparse"
+		}
+		if e.Compressed {
+			flags = "compressed"
+		}
+		fmt.Printf("%5d %16d %16s %12d %16s %10d %s\n", i, e.Offset, physical, e.Length, lcn, e.Clusters, flags)
+	}
+}
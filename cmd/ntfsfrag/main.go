@@ -0,0 +1,83 @@
+// Command ntfsfrag analyzes the fragmentation of an NTFS volume image.
+//
+// It reports the fragmentation of files, the $MFT and free space, along
+// with an overall score comparable to the Windows defragmenter's analysis.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gentlemanautomaton/ntfs"
+)
+
+func main() {
+	offset := flag.Int64("offset", 0, "byte offset of the volume within the image")
+	top := flag.Int("top", 20, "number of most fragmented files to list")
+	all := flag.Bool("all", false, "list the fragment count of every file")
+	flag.Parse()
+	path := flag.Arg(0)
+	if path == "" {
+		fmt.Fprintf(os.Stderr, "usage: %s [-offset bytes] [-top n] [-all] <volume image>\n", os.Args[0])
+		os.Exit(2)
+	}
+
+	// Open the raw file
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to open \"%s\": %s\n", path, err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to stat \"%s\": %s\n", path, err)
+		os.Exit(1)
+	}
+
+	r, err := ntfs.NewReader(io.NewSectionReader(f, *offset, fi.Size()-*offset))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to read NTFS volume: %v\n", err)
+		os.Exit(1)
+	}
+
+	var each func(ntfs.FileFragmentation)
+	if *all {
+		fmt.Printf("%10s %10s %12s %s\n", "record", "fragments", "clusters", "name")
+		each = func(frag ntfs.FileFragmentation) {
+			fmt.Printf("%10d %10d %12d %s\n", frag.File.SegmentNumber(), frag.Fragments, frag.Clusters, frag.Name)
+		}
+	}
+
+	report, err := r.AnalyzeFragmentation(*top, each)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to analyze NTFS volume: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("--------\nVolume fragmentation\n--------\n")
+	fmt.Printf("  Fragmented space:             %.0f%%\n", report.Score())
+	fmt.Printf("  Total clusters:               %d\n", report.Clusters)
+	fmt.Printf("  Allocated clusters:           %d\n", report.AllocatedClusters)
+	fmt.Printf("  Fragmented clusters:          %d\n", report.FragmentedClusters)
+	fmt.Printf("  Files:                        %d\n", report.Files)
+	fmt.Printf("  Fragmented files:             %d\n", report.FragmentedFiles)
+	fmt.Printf("  Average fragments per file:   %.2f\n", report.AverageFragments())
+	fmt.Printf("  MFT fragments:                %d (%d clusters)\n", report.MFTFragments, report.MFTClusters)
+	fmt.Printf("  Free clusters:                %d\n", report.FreeClusters)
+	fmt.Printf("  Free space extents:           %d\n", report.FreeExtents)
+	fmt.Printf("  Largest free space extent:    %d clusters\n", report.LargestFreeExtent)
+	fmt.Printf("  Average free space extent:    %.1f clusters\n", report.AverageFreeExtent())
+
+	if len(report.MostFragmented) == 0 {
+		return
+	}
+	fmt.Printf("--------\nMost fragmented files\n--------\n")
+	fmt.Printf("%10s %10s %12s %s\n", "record", "fragments", "clusters", "name")
+	for _, frag := range report.MostFragmented {
+		fmt.Printf("%10d %10d %12d %s\n", frag.File.SegmentNumber(), frag.Fragments, frag.Clusters, frag.Name)
+	}
+}
@@ -3,16 +3,12 @@ package main
 import (
 	"flag"
 	"fmt"
-	"io"
 	"os"
 
 	"github.com/gentlemanautomaton/ntfs"
-	"github.com/gentlemanautomaton/ntfs/mspart"
-	"github.com/rekby/gpt"
+	"github.com/gentlemanautomaton/ntfs/disk"
 )
 
-const sectorSize = 512 // TODO: Try to detect this automatically
-
 func main() {
 	flag.Parse()
 	path := flag.Arg(0)
@@ -31,39 +27,46 @@ func main() {
 	defer f.Close()
 	defer fmt.Printf("Closing \"%s\"\n", path)
 
-	// Seek past the protective MBR to the second sector (assuming sector size for now)
-	f.Seek(sectorSize, io.SeekStart)
+	fi, err := f.Stat()
+	if err != nil {
+		fmt.Printf("Unable to stat \"%s\": %s\n", path, err)
+		os.Exit(1)
+	}
 
-	// Read the GPT partition table
-	table, err := gpt.ReadTable(f, sectorSize)
+	// Read the partition table
+	layout, err := disk.Read(f, fi.Size())
 	if err != nil {
-		fmt.Printf("Unable to read GPT partition table: %s\n", err)
+		fmt.Printf("Unable to read partition table: %s\n", err)
 		return
 	}
 
-	fmt.Printf("--------\nDisk %v\n--------\n", table.Header.DiskGUID)
+	fmt.Printf("--------\nDisk %s (%d-byte sectors)", layout.Scheme, layout.SectorSize)
+	if !layout.DiskGUID.IsZero() {
+		fmt.Printf(" %s", layout.DiskGUID)
+	}
+	fmt.Printf("\n--------\n")
 
-	for _, part := range table.Partitions {
-		if part.IsEmpty() {
-			continue
+	for _, part := range layout.Partitions {
+		if part.Name != "" {
+			fmt.Printf("Partition %d %s: %s Range[%d:%d]\n", part.Number, part.Name, part.TypeName(), part.Offset, part.Offset+part.Length)
+		} else {
+			fmt.Printf("Partition %d: %s Range[%d:%d]\n", part.Number, part.TypeName(), part.Offset, part.Offset+part.Length)
 		}
-
-		fmt.Printf("Partition %s: %s Range[%d:%d]\n", part.Name(), part.Type, part.FirstLBA, part.LastLBA)
-		if part.Type != mspart.BasicData {
+		if !part.MayContainNTFS() {
 			continue
 		}
 
-		start := int64(part.FirstLBA * table.SectorSize)
-		end := int64(part.LastLBA*table.SectorSize + table.SectorSize)
+		start := part.Offset
+		end := part.Offset + part.Length
 		fmt.Printf("  Partition Start:              %d\n", start)
 		fmt.Printf("  Partition End:                %d\n", end)
 		fmt.Printf("  Partition Size:               %d\n", end-start)
 
-		section := io.NewSectionReader(f, start, end-start)
+		section := part.Open(f)
 
 		r, err := ntfs.NewReader(section)
 		if err != nil {
-			fmt.Printf("  Unable to read basic data volume NTFS volume boot record: %v\n", err)
+			fmt.Printf("  Unable to read NTFS volume boot record: %v\n", err)
 			continue
 		}
 
@@ -80,8 +83,24 @@ func main() {
 		fmt.Printf("  VolumeSerialNumber:           %d\n", vbr.VolumeSerialNumber)
 		fmt.Printf("  Checksum:                     %d\n", vbr.Checksum)
 
+		if info, err := r.VolumeInfo(); err != nil {
+			fmt.Printf("  Unable to read volume information: %v\n", err)
+		} else {
+			fmt.Printf("  VolumeLabel:                  %s\n", info.Label)
+			fmt.Printf("  Version:                      %s\n", info.Version())
+			fmt.Printf("  Flags:                        %s\n", info.Flags)
+		}
+
+		if diffs, err := r.CheckMirror(); err != nil {
+			fmt.Printf("  Unable to check $MFTMirr: %v\n", err)
+		} else {
+			for _, diff := range diffs {
+				fmt.Printf("  MFTMirr mismatch:             %s\n", diff)
+			}
+		}
+
 		mft := ntfs.MFT{
-			SectorSize:  sectorSize,
+			SectorSize:  int64(vbr.BytesPerSector),
 			ClusterSize: int64(vbr.ClusterSize()),
 			RecordSize:  int64(vbr.FileRecordSize()),
 			BaseAddr:    int64(vbr.MFT) * int64(vbr.ClusterSize()),
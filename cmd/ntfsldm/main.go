@@ -0,0 +1,93 @@
+// Command ntfsldm lists the volumes of Windows dynamic disks.
+//
+// Every disk of a disk group that holds data for a volume must be provided
+// for the volume to be read. Each volume that can be read is opened as an
+// NTFS volume and its label is reported.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/gentlemanautomaton/ntfs"
+	"github.com/gentlemanautomaton/ntfs/ldm"
+)
+
+func main() {
+	flag.Parse()
+	if flag.NArg() == 0 {
+		fmt.Fprintf(os.Stderr, "usage: %s <disk image> [<disk image>...]\n", os.Args[0])
+		os.Exit(2)
+	}
+
+	var members []*ldm.Member
+	for _, path := range flag.Args() {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Unable to open \"%s\": %s\n", path, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+
+		fi, err := f.Stat()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Unable to stat \"%s\": %s\n", path, err)
+			os.Exit(1)
+		}
+
+		m, err := ldm.Open(f, fi.Size())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Unable to read LDM database of \"%s\": %s\n", path, err)
+			os.Exit(1)
+		}
+		members = append(members, m)
+	}
+
+	group, err := ldm.NewGroup(members...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to assemble disk group: %s\n", err)
+		os.Exit(1)
+	}
+
+	db := group.Database
+	fmt.Printf("--------\nDisk group %s %s\n--------\n", db.DiskGroup.Name, db.VMDB.DiskGroupGUID)
+	for _, d := range db.Disks {
+		fmt.Printf("Disk %-12s %s\n", d.Name, d.GUID)
+	}
+	for _, d := range group.MissingDisks() {
+		fmt.Printf("Missing disk %s %s\n", d.Name, d.GUID)
+	}
+
+	for _, v := range group.Volumes() {
+		fmt.Printf("--------\nVolume %s %s\n--------\n", v.Name, v.DriveHint)
+		fmt.Printf("  Layout:                       %s\n", db.Layout(v))
+		fmt.Printf("  State:                        %s\n", v.State)
+		fmt.Printf("  Size:                         %d sectors\n", v.Size)
+		fmt.Printf("  PartitionType:                %s\n", v.PartitionType)
+		fmt.Printf("  GUID:                         %s\n", v.GUID)
+		for _, c := range db.VolumeComponents(v) {
+			for _, p := range db.ComponentPartitions(c) {
+				d, _ := db.Disk(p.DiskID)
+				fmt.Printf("  Partition %-8s %-8s column %d offset %d start %d size %d\n", p.Name, d.Name, p.Index, p.VolumeOffset, p.Start, p.Size)
+			}
+		}
+
+		section, err := group.OpenVolume(v)
+		if err != nil {
+			fmt.Printf("  Unable to open volume: %v\n", err)
+			continue
+		}
+		r, err := ntfs.NewReader(section)
+		if err != nil {
+			fmt.Printf("  Unable to read NTFS volume: %v\n", err)
+			continue
+		}
+		if info, err := r.VolumeInfo(); err != nil {
+			fmt.Printf("  Unable to read volume information: %v\n", err)
+		} else {
+			fmt.Printf("  VolumeLabel:                  %s\n", info.Label)
+			fmt.Printf("  Version:                      %s\n", info.Version())
+		}
+	}
+}
@@ -0,0 +1,298 @@
+// Command ntfsmap renders the allocation map of an NTFS volume image as a
+// PNG heatmap.
+//
+// Each pixel covers a range of clusters and is colored according to the
+// owners of those clusters, as derived from the data runs of every file
+// record and the cluster bitmap.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/gentlemanautomaton/ntfs"
+	"github.com/gentlemanautomaton/ntfs/attrtype"
+)
+
+// category is a classification of cluster owners.
+type category int
+
+// Cluster owner categories.
+const (
+	free category = iota
+	mft
+	system
+	directory
+	user
+	bad
+	unowned // Allocated in $Bitmap but not owned by any file
+	categories
+)
+
+var names = [categories]string{
+	free:      "Free",
+	mft:       "MFT",
+	system:    "System files",
+	directory: "Directories",
+	user:      "User files",
+	bad:       "Bad clusters",
+	unowned:   "Allocated, unowned",
+}
+
+var colors = [categories]color.RGBA{
+	free:      {0xFF, 0xFF, 0xFF, 0xFF},
+	mft:       {0x2E, 0x7D, 0x32, 0xFF},
+	system:    {0x6A, 0x1B, 0x9A, 0xFF},
+	directory: {0xF9, 0xA8, 0x25, 0xFF},
+	user:      {0x15, 0x65, 0xC0, 0xFF},
+	bad:       {0xC6, 0x28, 0x28, 0xFF},
+	unowned:   {0x75, 0x75, 0x75, 0xFF},
+}
+
+// firstUserRecord is the first file record number that isn't reserved for
+// system files.
+const firstUserRecord = 24
+
+// extent is a range of clusters owned by a file.
+type extent struct {
+	lcn    int64
+	length int64
+}
+
+// allocationMap accumulates the number of clusters of each category that
+// are covered by each pixel.
+type allocationMap struct {
+	clusters int64
+	scale    int64 // Clusters per pixel
+	counts   [][categories]int64
+	totals   [categories]int64
+}
+
+// add records length clusters starting at lcn as belonging to cat.
+func (m *allocationMap) add(lcn, length int64, cat category) {
+	if lcn < 0 || lcn >= m.clusters {
+		return
+	}
+	if end := m.clusters - lcn; length > end {
+		length = end
+	}
+	m.totals[cat] += length
+	for length > 0 {
+		pixel := lcn / m.scale
+		n := (pixel+1)*m.scale - lcn
+		if n > length {
+			n = length
+		}
+		m.counts[pixel][cat] += n
+		lcn += n
+		length -= n
+	}
+}
+
+// bitRun returns the value of bit lcn of bitmap and the number of
+// consecutive bits before end that share it. Whole bytes are skipped at
+// once.
+func bitRun(bitmap ntfs.Bitmap, lcn, end int64) (set bool, n int64) {
+	set = bitmap.Bit(lcn)
+	var full byte
+	if set {
+		full = 0xFF
+	}
+	i := lcn
+	for i < end {
+		if i/8 >= int64(len(bitmap)) {
+			// Bits beyond the end of the bitmap are not set
+			if !set {
+				i = end
+			}
+			break
+		}
+		if i%8 == 0 && i+8 <= end && bitmap[i/8] == full {
+			i += 8
+			continue
+		}
+		if bitmap.Bit(i) != set {
+			break
+		}
+		i++
+	}
+	return set, i - lcn
+}
+
+// color blends the colors of the categories covered by a pixel, weighted
+// by their cluster counts.
+func (m *allocationMap) color(pixel int) color.RGBA {
+	var r, g, b, total int64
+	for cat, n := range m.counts[pixel] {
+		c := colors[cat]
+		r += int64(c.R) * n
+		g += int64(c.G) * n
+		b += int64(c.B) * n
+		total += n
+	}
+	if total == 0 {
+		return color.RGBA{0, 0, 0, 0xFF}
+	}
+	return color.RGBA{uint8(r / total), uint8(g / total), uint8(b / total), 0xFF}
+}
+
+func main() {
+	offset := flag.Int64("offset", 0, "byte offset of the volume within the image")
+	width := flag.Int("width", 512, "width of the image in pixels")
+	scale := flag.Int64("scale", 0, "number of clusters per pixel (0 to fit a square image)")
+	output := flag.String("o", "allocation.png", "path of the PNG file to write")
+	flag.Parse()
+	path := flag.Arg(0)
+	if path == "" || *width <= 0 {
+		fmt.Fprintf(os.Stderr, "usage: %s [-offset bytes] [-width pixels] [-scale clusters] [-o file] <volume image>\n", os.Args[0])
+		os.Exit(2)
+	}
+
+	// Open the raw file
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to open \"%s\": %s\n", path, err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to stat \"%s\": %s\n", path, err)
+		os.Exit(1)
+	}
+
+	r, err := ntfs.NewReader(io.NewSectionReader(f, *offset, fi.Size()-*offset))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to read NTFS volume: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Determine the geometry of the image
+	boot := r.BootRecord()
+	m := allocationMap{
+		clusters: boot.Clusters(),
+		scale:    *scale,
+	}
+	if m.scale <= 0 {
+		pixels := int64(*width) * int64(*width)
+		m.scale = (m.clusters + pixels - 1) / pixels
+		if m.scale < 1 {
+			m.scale = 1
+		}
+	}
+	pixels := (m.clusters + m.scale - 1) / m.scale
+	height := int((pixels + int64(*width) - 1) / int64(*width))
+	m.counts = make([][categories]int64, pixels)
+
+	// Classify the clusters owned by each file. Extension records are
+	// classified by their base record.
+	var owned []extent
+	err = r.Walk(func(file *ntfs.File, err error) error {
+		if err != nil || !file.Header.InUse() {
+			return nil
+		}
+		ref := file.Reference()
+		id, dir := ref.SegmentNumber(), file.Header.Directory()
+		if base := file.Header.BaseFileRecordSegment; base != (ntfs.FileReference{}) {
+			id = base.SegmentNumber()
+			if baseFile, err := r.File(id); err == nil {
+				dir = baseFile.Header.Directory()
+			}
+		}
+		for a := range file.Attributes {
+			attr := &file.Attributes[a]
+			if attr.Header.Resident() {
+				continue
+			}
+			cat := user
+			switch {
+			case id == ntfs.RecordMFT:
+				cat = mft
+			case id == ntfs.RecordBadClus && attr.Header.TypeCode == attrtype.Data && attr.Name == "$Bad":
+				cat = bad
+			case id < firstUserRecord:
+				cat = system
+			case dir:
+				cat = directory
+			}
+			runlist, err := attr.Runlist()
+			if err != nil {
+				continue
+			}
+			for _, e := range runlist {
+				if e.Sparse {
+					continue
+				}
+				owned = append(owned, extent{lcn: e.LCN, length: int64(e.Length)})
+				m.add(e.LCN, int64(e.Length), cat)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to read the master file table: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Classify the remaining clusters as free or allocated
+	bitmap, err := r.ClusterBitmap()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to read the cluster bitmap: %v\n", err)
+		os.Exit(1)
+	}
+	sort.Slice(owned, func(i, j int) bool { return owned[i].lcn < owned[j].lcn })
+	owned = append(owned, extent{lcn: m.clusters})
+	var lcn int64
+	for _, e := range owned {
+		end := e.lcn
+		if end > m.clusters {
+			end = m.clusters
+		}
+		for lcn < end {
+			set, n := bitRun(bitmap, lcn, end)
+			if set {
+				m.add(lcn, n, unowned)
+			} else {
+				m.add(lcn, n, free)
+			}
+			lcn += n
+		}
+		if end = e.lcn + e.length; end > lcn {
+			lcn = end
+		}
+	}
+
+	// Render the image
+	img := image.NewRGBA(image.Rect(0, 0, *width, height))
+	for pixel := range m.counts {
+		img.SetRGBA(pixel%*width, pixel / *width, m.color(pixel))
+	}
+
+	out, err := os.Create(*output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to create \"%s\": %s\n", *output, err)
+		os.Exit(1)
+	}
+	if err := png.Encode(out, img); err != nil {
+		out.Close()
+		fmt.Fprintf(os.Stderr, "Unable to write \"%s\": %s\n", *output, err)
+		os.Exit(1)
+	}
+	if err := out.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to write \"%s\": %s\n", *output, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Wrote %dx%d image to \"%s\" (%d clusters per pixel)\n", *width, height, *output, m.scale)
+	for cat := category(0); cat < categories; cat++ {
+		c := colors[cat]
+		fmt.Printf("  #%02X%02X%02X %-20s %d clusters\n", c.R, c.G, c.B, names[cat]+":", m.totals[cat])
+	}
+}
@@ -0,0 +1,85 @@
+// Command ntfsmftexport exports every record of the master file table of
+// an NTFS volume image as CSV or JSON Lines.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gentlemanautomaton/ntfs"
+	"github.com/gentlemanautomaton/ntfs/mftexport"
+)
+
+func main() {
+	offset := flag.Int64("offset", 0, "byte offset of the volume within the image")
+	standalone := flag.Bool("mft", false, "read a standalone $MFT file instead of a volume image")
+	format := flag.String("format", "csv", "output format: csv or jsonl")
+	output := flag.String("o", "", "output file (default: standard output)")
+	flag.Parse()
+	path := flag.Arg(0)
+	if path == "" {
+		fmt.Fprintf(os.Stderr, "usage: %s [-offset bytes] [-mft] [-format csv|jsonl] [-o file] <volume image or $MFT file>\n", os.Args[0])
+		os.Exit(2)
+	}
+
+	// Open the raw file
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to open \"%s\": %s\n", path, err)
+		os.Exit(2)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to stat \"%s\": %s\n", path, err)
+		os.Exit(2)
+	}
+
+	var r *ntfs.Reader
+	if *standalone {
+		r, err = ntfs.OpenMFT(io.NewSectionReader(f, *offset, fi.Size()-*offset))
+	} else {
+		r, err = ntfs.NewReader(io.NewSectionReader(f, *offset, fi.Size()-*offset))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to read NTFS volume: %v\n", err)
+		os.Exit(2)
+	}
+	for _, warning := range r.Warnings() {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", warning)
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		if out, err = os.Create(*output); err != nil {
+			fmt.Fprintf(os.Stderr, "Unable to create \"%s\": %s\n", *output, err)
+			os.Exit(2)
+		}
+		defer out.Close()
+	}
+	w := bufio.NewWriter(out)
+
+	var enc mftexport.Encoder
+	switch *format {
+	case "csv":
+		enc = mftexport.NewCSVEncoder(w)
+	case "jsonl", "json":
+		enc = mftexport.NewJSONEncoder(w)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown output format \"%s\"\n", *format)
+		os.Exit(2)
+	}
+
+	err = mftexport.Export(r, enc)
+	if flushErr := w.Flush(); err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to export MFT records: %v\n", err)
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,80 @@
+// Command ntfsscan searches a disk image for NTFS volumes.
+//
+// It is intended for disks whose partition table is missing or damaged.
+// Each candidate volume is listed with a confidence score and the offset
+// that can be passed to the -offset flag of the other commands.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/gentlemanautomaton/ntfs"
+)
+
+func main() {
+	min := flag.Int("min", 0, "minimum confidence score of the candidates to list")
+	verify := flag.Bool("verify", false, "open each candidate with a reader and report the result")
+	flag.Parse()
+	path := flag.Arg(0)
+	if path == "" {
+		fmt.Fprintf(os.Stderr, "usage: %s [-min score] [-verify] <disk image>\n", os.Args[0])
+		os.Exit(2)
+	}
+
+	// Open the raw file
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to open \"%s\": %s\n", path, err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to stat \"%s\": %s\n", path, err)
+		os.Exit(1)
+	}
+
+	candidates, err := ntfs.ScanVolumes(f, fi.Size())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to scan \"%s\": %s\n", path, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%14s %14s %10s %s\n", "offset", "length", "confidence", "evidence")
+	for _, c := range candidates {
+		if c.Confidence < *min {
+			continue
+		}
+		fmt.Printf("%14d %14d %9d%% %s\n", c.Offset, c.Length, c.Confidence, evidence(c))
+		if *verify {
+			if _, err := ntfs.NewReader(c.Open(f)); err != nil {
+				fmt.Printf("%14s unable to open volume: %v\n", "", err)
+			} else {
+				fmt.Printf("%14s volume opened successfully\n", "")
+			}
+		}
+	}
+}
+
+func evidence(c ntfs.VolumeCandidate) string {
+	var s string
+	add := func(present bool, label string) {
+		if !present {
+			return
+		}
+		if s != "" {
+			s += ", "
+		}
+		s += label
+	}
+	add(c.Primary, "boot sector")
+	add(c.Backup, "backup boot sector")
+	add(c.Match, "boot sectors match")
+	add(c.MFT, "$MFT")
+	add(c.MFTMirror, "$MFTMirr")
+	add(c.Truncated, "truncated")
+	return s
+}
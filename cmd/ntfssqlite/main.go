@@ -0,0 +1,288 @@
+// Command ntfssqlite loads the metadata of an NTFS volume image into a
+// SQLite database for ad-hoc queries.
+//
+// The database holds the volume parameters, every readable file record
+// with its names, $DATA streams and extents, the records of the USN
+// change journal and the security descriptors of the volume.
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/gentlemanautomaton/ntfs"
+	"github.com/gentlemanautomaton/ntfs/attrtype"
+	"github.com/gentlemanautomaton/ntfs/mftexport"
+	_ "modernc.org/sqlite"
+)
+
+// timeFormat is the format of timestamps stored in the database.
+const timeFormat = "2006-01-02 15:04:05.0000000"
+
+func main() {
+	offset := flag.Int64("offset", 0, "byte offset of the volume within the image")
+	force := flag.Bool("f", false, "overwrite the database if it already exists")
+	flag.Parse()
+	path, dbPath := flag.Arg(0), flag.Arg(1)
+	if path == "" || dbPath == "" {
+		fmt.Fprintf(os.Stderr, "usage: %s [-offset bytes] [-f] <volume image> <database>\n", os.Args[0])
+		os.Exit(2)
+	}
+
+	// Open the raw file
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to open \"%s\": %s\n", path, err)
+		os.Exit(2)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to stat \"%s\": %s\n", path, err)
+		os.Exit(2)
+	}
+
+	r, err := ntfs.NewReader(io.NewSectionReader(f, *offset, fi.Size()-*offset))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to read NTFS volume: %v\n", err)
+		os.Exit(2)
+	}
+	for _, warning := range r.Warnings() {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", warning)
+	}
+
+	// Create the database
+	if _, err := os.Stat(dbPath); err == nil {
+		if !*force {
+			fmt.Fprintf(os.Stderr, "The database \"%s\" already exists\n", dbPath)
+			os.Exit(2)
+		}
+		if err := os.Remove(dbPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Unable to remove \"%s\": %s\n", dbPath, err)
+			os.Exit(2)
+		}
+	}
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to open database \"%s\": %v\n", dbPath, err)
+		os.Exit(2)
+	}
+	defer db.Close()
+
+	if err := load(db, r); err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to load database: %v\n", err)
+		db.Close()
+		os.Exit(2)
+	}
+}
+
+// load creates the tables of db and loads the metadata of r into them.
+func load(db *sql.DB, r *ntfs.Reader) error {
+	if _, err := db.Exec(schema); err != nil {
+		return fmt.Errorf("unable to create tables: %v", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := loadVolume(tx, r); err != nil {
+		return fmt.Errorf("unable to load volume: %v", err)
+	}
+	if err := loadRecords(tx, r); err != nil {
+		return fmt.Errorf("unable to load file records: %v", err)
+	}
+
+	// The USN journal and security descriptors are optional. Each is loaded
+	// within a savepoint so that a failure doesn't leave partial rows.
+	optional := []struct {
+		name string
+		desc string
+		load func(*sql.Tx, *ntfs.Reader) error
+	}{
+		{"usn", "USN journal", loadUSNJournal},
+		{"security", "security descriptors", loadSecurityDescriptors},
+	}
+	for _, section := range optional {
+		loadErr, err := withSavepoint(tx, section.name, func() error {
+			return section.load(tx, r)
+		})
+		if err != nil {
+			return fmt.Errorf("unable to load %s: %v", section.desc, err)
+		}
+		if loadErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: unable to load %s: %v\n", section.desc, loadErr)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(indexes); err != nil {
+		return fmt.Errorf("unable to create indexes: %v", err)
+	}
+	return nil
+}
+
+// withSavepoint calls fn within a savepoint of tx. If fn fails its changes
+// are rolled back and its error is returned as loadErr. If the savepoint
+// itself fails err is returned.
+func withSavepoint(tx *sql.Tx, name string, fn func() error) (loadErr, err error) {
+	if _, err := tx.Exec("SAVEPOINT " + name); err != nil {
+		return nil, err
+	}
+	if loadErr = fn(); loadErr != nil {
+		if _, err := tx.Exec("ROLLBACK TO " + name); err != nil {
+			return loadErr, err
+		}
+	}
+	_, err = tx.Exec("RELEASE " + name)
+	return loadErr, err
+}
+
+func loadVolume(tx *sql.Tx, r *ntfs.Reader) error {
+	boot := r.BootRecord()
+	var label, version, flags string
+	if info, err := r.VolumeInfo(); err == nil {
+		label, version, flags = info.Label, info.Version(), info.Flags.String()
+	}
+	_, err := tx.Exec(`INSERT INTO volume VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		int64(boot.VolumeSerialNumber), label, version, flags,
+		boot.BytesPerSector, boot.ClusterSize(), boot.Clusters(),
+		boot.FileRecordSize(), boot.IndexBlockSize(), int64(boot.MFT), int64(boot.MFTMirror))
+	return err
+}
+
+func loadRecords(tx *sql.Tx, r *ntfs.Reader) error {
+	insertRecord, err := tx.Prepare(`INSERT INTO records VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	insertName, err := tx.Prepare(`INSERT INTO names VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	insertStream, err := tx.Prepare(`INSERT INTO streams VALUES (?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	insertExtent, err := tx.Prepare(`INSERT INTO extents VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+
+	// Records that can't be read are inserted with only their record
+	// number and error
+	resolver := r.NewPathResolver()
+	id := int64(-1)
+	return r.Walk(func(file *ntfs.File, err error) error {
+		id++
+		if errors.Is(err, ntfs.ErrUnusedRecord) {
+			return nil
+		}
+		var rec *mftexport.Record
+		if err != nil {
+			rec = &mftexport.Record{Record: id, Error: err.Error()}
+		} else {
+			rec = mftexport.NewRecord(r, resolver, file)
+		}
+		_, err = insertRecord.Exec(rec.Record, rec.Sequence, rec.InUse, rec.Directory, rec.Flags,
+			rec.BaseRecord, rec.BaseSequence, int64(rec.LSN), rec.HardLinks, nullString(rec.Path),
+			formatTime(rec.Created), formatTime(rec.Modified), formatTime(rec.MFTModified), formatTime(rec.Accessed),
+			rec.FileAttributes, rec.OwnerID, rec.SecurityID, int64(rec.USN), rec.Size, rec.AllocatedSize,
+			rec.ReparseTag, nullString(rec.ObjectID), nullString(rec.Error))
+		if err != nil {
+			return err
+		}
+		for _, name := range rec.Names {
+			_, err := insertName.Exec(rec.Record, name.Name, name.Namespace, name.ParentRecord, name.ParentSequence,
+				formatTime(name.Created), formatTime(name.Modified), formatTime(name.MFTModified), formatTime(name.Accessed))
+			if err != nil {
+				return err
+			}
+		}
+		for _, stream := range rec.Streams {
+			_, err := insertStream.Exec(rec.Record, stream.Name, stream.Size, stream.AllocatedSize, stream.Resident, stream.Flags)
+			if err != nil {
+				return err
+			}
+			extents, err := r.Extents(file, attrtype.Data, stream.Name)
+			if err != nil {
+				continue
+			}
+			for _, e := range extents {
+				var lcn interface{}
+				if !e.Sparse {
+					lcn = e.LCN
+				}
+				_, err := insertExtent.Exec(rec.Record, stream.Name, int64(e.VCN), lcn, e.Clusters, e.Offset, e.Length, e.Sparse, e.Compressed)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func loadUSNJournal(tx *sql.Tx, r *ntfs.Reader) error {
+	insert, err := tx.Prepare(`INSERT INTO usn VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	err = r.USNJournal(func(record ntfs.USNRecord) error {
+		_, err := insert.Exec(record.USN, record.File.SegmentNumber(), record.File.SequenceNumber,
+			record.Parent.SegmentNumber(), record.Parent.SequenceNumber, formatTime(record.Timestamp),
+			uint32(record.Reason), record.Reason.String(), record.SourceInfo, record.SecurityID,
+			record.DOSFilePermissions.String(), record.FileName)
+		return err
+	})
+	if err == ntfs.ErrNoUSNJournal {
+		return nil
+	}
+	return err
+}
+
+func loadSecurityDescriptors(tx *sql.Tx, r *ntfs.Reader) error {
+	insert, err := tx.Prepare(`INSERT OR IGNORE INTO security_descriptors VALUES (?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	return r.SecurityDescriptors(func(entry ntfs.SecurityDescriptorEntry) error {
+		var owner, group interface{}
+		if sid, ok := entry.Descriptor.Owner(); ok {
+			owner = sid.String()
+		}
+		if sid, ok := entry.Descriptor.Group(); ok {
+			group = sid.String()
+		}
+		_, err := insert.Exec(entry.SecurityID, entry.Hash, owner, group, entry.Descriptor.Control(), []byte(entry.Descriptor))
+		return err
+	})
+}
+
+// formatTime returns t in the database timestamp format, or nil if t is
+// zero.
+func formatTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(timeFormat)
+}
+
+// nullString returns s, or nil if s is empty.
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
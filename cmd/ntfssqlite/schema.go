@@ -0,0 +1,124 @@
+package main
+
+// schema creates the tables of the database. Timestamps are stored as
+// UTC text in "YYYY-MM-DD HH:MM:SS.fffffff" form, which sorts correctly
+// and is understood by SQLite's date and time functions.
+const schema = `
+CREATE TABLE volume (
+	serial_number    INTEGER,
+	label            TEXT,
+	version          TEXT,
+	flags            TEXT,
+	sector_size      INTEGER,
+	cluster_size     INTEGER,
+	clusters         INTEGER,
+	file_record_size INTEGER,
+	index_block_size INTEGER,
+	mft_lcn          INTEGER,
+	mftmirr_lcn      INTEGER
+);
+
+CREATE TABLE records (
+	record          INTEGER PRIMARY KEY,
+	sequence        INTEGER NOT NULL,
+	in_use          INTEGER NOT NULL,
+	directory       INTEGER NOT NULL,
+	flags           TEXT,
+	base_record     INTEGER,
+	base_sequence   INTEGER,
+	lsn             INTEGER,
+	hard_links      INTEGER,
+	path            TEXT,
+	created         TEXT,
+	modified        TEXT,
+	mft_modified    TEXT,
+	accessed        TEXT,
+	file_attributes TEXT,
+	owner_id        INTEGER,
+	security_id     INTEGER,
+	usn             INTEGER,
+	size            INTEGER,
+	allocated_size  INTEGER,
+	reparse_tag     INTEGER,
+	object_id       TEXT,
+	error           TEXT
+);
+
+CREATE TABLE names (
+	record          INTEGER NOT NULL REFERENCES records (record),
+	name            TEXT NOT NULL,
+	namespace       TEXT NOT NULL,
+	parent_record   INTEGER NOT NULL,
+	parent_sequence INTEGER NOT NULL,
+	created         TEXT,
+	modified        TEXT,
+	mft_modified    TEXT,
+	accessed        TEXT
+);
+
+CREATE TABLE streams (
+	record         INTEGER NOT NULL REFERENCES records (record),
+	name           TEXT NOT NULL,
+	size           INTEGER,
+	allocated_size INTEGER,
+	resident       INTEGER NOT NULL,
+	flags          TEXT
+);
+
+CREATE TABLE extents (
+	record     INTEGER NOT NULL REFERENCES records (record),
+	stream     TEXT NOT NULL,
+	vcn        INTEGER NOT NULL,
+	lcn        INTEGER,
+	clusters   INTEGER NOT NULL,
+	offset     INTEGER NOT NULL,
+	length     INTEGER NOT NULL,
+	sparse     INTEGER NOT NULL,
+	compressed INTEGER NOT NULL
+);
+
+CREATE TABLE usn (
+	usn             INTEGER NOT NULL,
+	record          INTEGER NOT NULL,
+	sequence        INTEGER NOT NULL,
+	parent_record   INTEGER NOT NULL,
+	parent_sequence INTEGER NOT NULL,
+	timestamp       TEXT,
+	reason          INTEGER NOT NULL,
+	reason_text     TEXT,
+	source_info     INTEGER,
+	security_id     INTEGER,
+	file_attributes TEXT,
+	name            TEXT
+);
+
+CREATE TABLE security_descriptors (
+	security_id INTEGER PRIMARY KEY,
+	hash        INTEGER NOT NULL,
+	owner       TEXT,
+	grp         TEXT,
+	control     INTEGER,
+	descriptor  BLOB NOT NULL
+);
+`
+
+// indexes creates the indexes of the database. They are created after the
+// data has been loaded, which is considerably faster.
+const indexes = `
+CREATE INDEX records_path ON records (path);
+CREATE INDEX records_created ON records (created);
+CREATE INDEX records_modified ON records (modified);
+CREATE INDEX records_mft_modified ON records (mft_modified);
+CREATE INDEX records_accessed ON records (accessed);
+CREATE INDEX records_security_id ON records (security_id);
+CREATE INDEX names_record ON names (record);
+CREATE INDEX names_parent ON names (parent_record, parent_sequence);
+CREATE INDEX names_created ON names (created);
+CREATE INDEX names_modified ON names (modified);
+CREATE INDEX streams_record ON streams (record);
+CREATE INDEX extents_record ON extents (record);
+CREATE INDEX extents_lcn ON extents (lcn);
+CREATE INDEX usn_record ON usn (record, sequence);
+CREATE INDEX usn_parent ON usn (parent_record, parent_sequence);
+CREATE INDEX usn_timestamp ON usn (timestamp);
+`
@@ -0,0 +1,87 @@
+// Command ntfstimecheck reports files on an NTFS volume image with
+// timestamps that show common signs of manipulation.
+//
+// Each file with anomalies is written to standard output as a line of
+// JSON. The command exits with status 1 if any anomalies are found.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/gentlemanautomaton/ntfs"
+)
+
+func main() {
+	offset := flag.Int64("offset", 0, "byte offset of the volume within the image")
+	reference := flag.String("reference", "", "RFC 3339 reference time for future timestamps (default: newest USN journal record)")
+	text := flag.Bool("text", false, "write a human-readable report instead of JSON lines")
+	flag.Parse()
+	path := flag.Arg(0)
+	if path == "" {
+		fmt.Fprintf(os.Stderr, "usage: %s [-offset bytes] [-reference time] [-text] <volume image>\n", os.Args[0])
+		os.Exit(2)
+	}
+
+	var ref time.Time
+	if *reference != "" {
+		var err error
+		if ref, err = time.Parse(time.RFC3339, *reference); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid reference time \"%s\": %v\n", *reference, err)
+			os.Exit(2)
+		}
+	}
+
+	// Open the raw file
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to open \"%s\": %s\n", path, err)
+		os.Exit(2)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to stat \"%s\": %s\n", path, err)
+		os.Exit(2)
+	}
+
+	r, err := ntfs.NewReader(io.NewSectionReader(f, *offset, fi.Size()-*offset))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to read NTFS volume: %v\n", err)
+		os.Exit(2)
+	}
+	for _, warning := range r.Warnings() {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", warning)
+	}
+
+	var (
+		enc   = json.NewEncoder(os.Stdout)
+		found int
+	)
+	err = r.AnalyzeTimestamps(ref, func(finding ntfs.TimestampFinding) error {
+		found++
+		if !*text {
+			return enc.Encode(finding)
+		}
+		fmt.Printf("Record %d (%s)\n", finding.Record, finding.Path)
+		for _, anomaly := range finding.Anomalies {
+			fmt.Printf("  %s\n", anomaly)
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to analyze timestamps: %v\n", err)
+		os.Exit(2)
+	}
+
+	fmt.Fprintf(os.Stderr, "%d files with timestamp anomalies found\n", found)
+	if found > 0 {
+		f.Close()
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,214 @@
+// Command ntfstimeline writes a timeline of an NTFS volume image in the
+// body file format used by The Sleuth Kit's mactime and by plaso.
+//
+// Every file record is included, even those that are no longer in use.
+// Paths use forward slashes, as The Sleuth Kit does.
+// Each link to a file produces a line with its $STANDARD_INFORMATION
+// timestamps for each of its $DATA streams, and a line with its
+// $FILE_NAME timestamps.
+//
+// https://wiki.sleuthkit.org/index.php?title=Body_file
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gentlemanautomaton/ntfs"
+	"github.com/gentlemanautomaton/ntfs/attrtype"
+	"github.com/gentlemanautomaton/ntfs/fileattr"
+)
+
+func main() {
+	offset := flag.Int64("offset", 0, "byte offset of the volume within the image")
+	standalone := flag.Bool("mft", false, "read a standalone $MFT file instead of a volume image")
+	prefix := flag.String("prefix", "", "prefix for every path, such as a drive letter")
+	flag.Parse()
+	path := flag.Arg(0)
+	if path == "" {
+		fmt.Fprintf(os.Stderr, "usage: %s [-offset bytes] [-mft] [-prefix path] <volume image or $MFT file>\n", os.Args[0])
+		os.Exit(2)
+	}
+
+	// Open the raw file
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to open \"%s\": %s\n", path, err)
+		os.Exit(2)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to stat \"%s\": %s\n", path, err)
+		os.Exit(2)
+	}
+
+	var r *ntfs.Reader
+	if *standalone {
+		r, err = ntfs.OpenMFT(io.NewSectionReader(f, *offset, fi.Size()-*offset))
+	} else {
+		r, err = ntfs.NewReader(io.NewSectionReader(f, *offset, fi.Size()-*offset))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to read NTFS volume: %v\n", err)
+		os.Exit(2)
+	}
+	for _, warning := range r.Warnings() {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", warning)
+	}
+
+	var (
+		w        = bufio.NewWriter(os.Stdout)
+		resolver = r.NewPathResolver()
+		skipped  int
+	)
+	err = r.Walk(func(file *ntfs.File, err error) error {
+		if err != nil || !file.Header.BaseFileRecordSegment.IsZero() {
+			return nil
+		}
+		names, err := r.Names(file)
+		if err != nil {
+			skipped++
+			return nil
+		}
+		if len(names) == 0 {
+			return nil
+		}
+
+		var si ntfs.StandardInformation
+		siAttr, ok := file.Attribute(attrtype.StandardInformation)
+		if ok {
+			si.UnmarshalBinary(siAttr.ResidentValue)
+		}
+		mode := modeString(file, &si)
+		content := contentAttributes(r, file, siAttr)
+
+		for _, name := range names {
+			if !name.Flags.Long() {
+				continue
+			}
+			path := resolver.LinkPath(name)
+			if file.ID == ntfs.RecordRoot {
+				path = ntfs.PathSeparator
+			}
+			path = *prefix + strings.Replace(path, ntfs.PathSeparator, "/", -1)
+			suffix := ""
+			if !file.Header.InUse() {
+				suffix = " (deleted)"
+			}
+			for _, c := range content {
+				writeLine(w, path+c.suffix+suffix, address(file.ID, c.code, c.instance), mode, c.size,
+					si.FileRead, si.FileModification, si.MFTModification, si.FileCreation)
+			}
+			size := int64(0)
+			if len(content) > 0 {
+				size = content[0].size
+			}
+			writeLine(w, path+" ($FILE_NAME)"+suffix, address(file.ID, attrtype.FileName, nameInstance(file, name)), mode, size,
+				name.FileRead, name.FileModification, name.MFTModification, name.FileCreation)
+		}
+		return nil
+	})
+	if flushErr := w.Flush(); err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to write timeline: %v\n", err)
+		os.Exit(2)
+	}
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "%d records could not be read\n", skipped)
+	}
+}
+
+// content describes an attribute that holds the content of a file.
+type content struct {
+	code     attrtype.Code
+	instance uint16
+	suffix   string // The stream name suffix for the path
+	size     int64
+}
+
+// contentAttributes returns the attributes of file that are reported with
+// its $STANDARD_INFORMATION timestamps. These are the $DATA streams of
+// files and the $I30 index root of directories.
+func contentAttributes(r *ntfs.Reader, file *ntfs.File, si *ntfs.Attribute) []content {
+	if file.Header.Directory() {
+		if root, ok := file.NamedAttribute(attrtype.IndexRoot, ntfs.FileNameIndex); ok {
+			size := int64(len(root.ResidentValue))
+			if alloc, ok := file.NamedAttribute(attrtype.IndexAllocation, ntfs.FileNameIndex); ok {
+				size = alloc.Nonresident.DataLength
+			}
+			return []content{{code: attrtype.IndexRoot, instance: root.Header.Instance, size: size}}
+		}
+	}
+
+	var contents []content
+	streams, _ := r.Streams(file)
+	for _, stream := range streams {
+		c := content{code: attrtype.Data, instance: stream.Instance, size: stream.Size}
+		if stream.Name != "" {
+			c.suffix = ":" + stream.Name
+		}
+		contents = append(contents, c)
+	}
+	if len(contents) == 0 && si != nil {
+		contents = append(contents, content{code: attrtype.StandardInformation, instance: si.Header.Instance})
+	}
+	return contents
+}
+
+// nameInstance returns the attribute instance of the file name attribute
+// of file that matches name.
+func nameInstance(file *ntfs.File, name ntfs.FileName) uint16 {
+	for a := range file.Attributes {
+		attr := &file.Attributes[a]
+		if attr.Header.TypeCode != attrtype.FileName {
+			continue
+		}
+		var fn ntfs.FileName
+		if fn.UnmarshalBinary(attr.ResidentValue) == nil && fn.Value == name.Value && fn.ParentDirectory == name.ParentDirectory {
+			return attr.Header.Instance
+		}
+	}
+	return 0
+}
+
+// address returns the Sleuth Kit style "record-type-id" address of an
+// attribute.
+func address(id int64, code attrtype.Code, instance uint16) string {
+	return fmt.Sprintf("%d-%d-%d", id, uint32(code), instance)
+}
+
+// modeString returns the Sleuth Kit style mode string of file.
+func modeString(file *ntfs.File, si *ntfs.StandardInformation) string {
+	perm := "rwxrwxrwx"
+	if si.DOSFilePermissions&fileattr.ReadOnly != 0 {
+		perm = "r-xr-xr-x"
+	}
+	if file.Header.Directory() {
+		return "d/d" + perm
+	}
+	return "r/r" + perm
+}
+
+// writeLine writes a body file line to w.
+func writeLine(w io.Writer, name, inode, mode string, size int64, atime, mtime, ctime, crtime time.Time) {
+	fmt.Fprintf(w, "0|%s|%s|%s|0|0|%d|%d|%d|%d|%d\n", name, inode, mode, size,
+		unixTime(atime), unixTime(mtime), unixTime(ctime), unixTime(crtime))
+}
+
+// unixTime returns t as seconds since the unix epoch, or zero if t is
+// before it.
+func unixTime(t time.Time) int64 {
+	if t.Unix() < 0 {
+		return 0
+	}
+	return t.Unix()
+}
@@ -5,6 +5,9 @@ import "errors"
 var (
 	// Label is the OEM ID of NTFS file system boot records.
 	Label = [8]byte{'N', 'T', 'F', 'S', ' ', ' ', ' ', ' '}
+
+	// FileSignature is the multi-sector header signature of file records.
+	FileSignature = [4]byte{'F', 'I', 'L', 'E'}
 )
 
 var (
@@ -22,6 +25,12 @@ var (
 	// beyond the bounds of its containing record.
 	ErrInvalidUnicode = errors.New("invalid unicode data")
 
+	// ErrAttributeLengthInvalid is returned when an attribute record has a
+	// length of zero.
+	//
+	// This typically is indicative of MFT corruption.
+	ErrAttributeLengthInvalid = errors.New("attribute record has an invalid length")
+
 	// ErrAttributeNameOutOfBounds is returned when an attribute name exceeds
 	// the the bounds of its containing record.
 	//
@@ -34,9 +43,172 @@ var (
 	// This typically is indicative of MFT corruption.
 	ErrAttributeValueOutOfBounds = errors.New("attribute value exceeds the bounds of its file record segment")
 
+	// ErrMappingPairsOutOfBounds is returned when the mapping pairs of a
+	// non-resident attribute exceed the bounds of its containing record.
+	//
+	// This typically is indicative of MFT corruption.
+	ErrMappingPairsOutOfBounds = errors.New("attribute mapping pairs exceed the bounds of its file record segment")
+
 	// ErrFileNameOutOfBounds is returned when a the value of a file name attribute
 	// exceeds the bounds of its containing record.
 	//
 	// This typically is indicative of MFT corruption.
 	ErrFileNameOutOfBounds = errors.New("file name value exceeds the bounds of its file record segment")
+
+	// ErrVolumeInformationMissing is returned when the $Volume system file
+	// does not contain a $VOLUME_INFORMATION attribute.
+	//
+	// This typically is indicative of MFT corruption.
+	ErrVolumeInformationMissing = errors.New("the $Volume system file does not contain volume information")
+
+	// ErrDirtyVolume is returned or recorded as a warning when a reader
+	// encounters a volume that is marked dirty.
+	ErrDirtyVolume = errors.New("the volume is marked dirty and may be inconsistent")
+
+	// ErrUnsupportedVersion is returned or recorded as a warning when a reader
+	// encounters a volume with an unsupported NTFS version.
+	ErrUnsupportedVersion = errors.New("the volume has an unsupported NTFS version")
+
+	// ErrInvalidBootSignature is returned when a boot sector does not end
+	// with the 0x55AA end-of-sector marker.
+	ErrInvalidBootSignature = errors.New("boot sector does not contain a valid end-of-sector marker")
+
+	// ErrInvalidChecksum is returned when a boot sector's checksum does not
+	// match its contents.
+	ErrInvalidChecksum = errors.New("boot sector checksum does not match its contents")
+
+	// ErrInvalidParameterBlock is returned when a BIOS parameter block
+	// describes an implausible volume geometry.
+	ErrInvalidParameterBlock = errors.New("BIOS parameter block contains invalid volume geometry")
+
+	// ErrBootSectorMismatch is recorded as a warning when the primary and
+	// backup boot sectors of a volume are both valid but differ.
+	ErrBootSectorMismatch = errors.New("the primary and backup boot sectors differ")
+
+	// ErrBackupBootSectorInvalid is recorded as a warning when the backup boot
+	// sector of a volume is missing or invalid.
+	ErrBackupBootSectorInvalid = errors.New("the backup boot sector is missing or invalid")
+
+	// ErrBackupBootSectorUsed is recorded as a warning when the primary boot
+	// sector of a volume is invalid and the reader falls back to the backup
+	// boot sector.
+	ErrBackupBootSectorUsed = errors.New("the primary boot sector is invalid, the backup boot sector was used instead")
+
+	// ErrFixupOutOfBounds is returned when the update sequence array of a
+	// multi-sector record exceeds the bounds of the record.
+	//
+	// This typically is indicative of MFT corruption.
+	ErrFixupOutOfBounds = errors.New("update sequence array exceeds the bounds of its record")
+
+	// ErrFixupMismatch is returned when the update sequence number at the end
+	// of a sector does not match the update sequence array of its record.
+	//
+	// This typically is indicative of an incomplete write.
+	ErrFixupMismatch = errors.New("update sequence number mismatch")
+
+	// ErrInvalidFileSignature is returned when a file record does not begin
+	// with the "FILE" signature.
+	ErrInvalidFileSignature = errors.New("file record does not contain a valid signature")
+
+	// ErrUnusedRecord is returned when a file record is entirely zero,
+	// which is the case for records that have never been used.
+	ErrUnusedRecord = errors.New("file record has never been used")
+
+	// ErrInvalidRecordNumber is returned when attempting to read a file
+	// record with a negative record number.
+	ErrInvalidRecordNumber = errors.New("invalid file record number")
+
+	// ErrMirrorRecordUsed is recorded as a warning when a file record could
+	// not be read from $MFT and its copy in $MFTMirr was used instead.
+	ErrMirrorRecordUsed = errors.New("the $MFT copy of a file record is invalid, the $MFTMirr copy was used instead")
+
+	// ErrCompressedAttribute is returned when attempting to read the data of
+	// a compressed attribute.
+	ErrCompressedAttribute = errors.New("compressed attribute data is not supported")
+
+	// ErrUnmappedVCN is returned when attempting to read attribute data from
+	// a virtual cluster that isn't mapped by the attribute's data runs.
+	ErrUnmappedVCN = errors.New("virtual cluster number is not mapped by the attribute's data runs")
+
+	// ErrAttributeMissing is returned when a file record does not contain
+	// a required attribute.
+	ErrAttributeMissing = errors.New("file record does not contain the required attribute")
+
+	// ErrMFTDataUnavailable is recorded as a warning when the data runs of
+	// the $MFT system file cannot be read. File records are then assumed to
+	// be contiguous. It is returned by Walk and Check when the number of file
+	// records can't be determined either.
+	ErrMFTDataUnavailable = errors.New("the data runs of the $MFT system file are unavailable")
+
+	// ErrIndexOutOfBounds is returned when an index entry exceeds the bounds
+	// of its index node.
+	//
+	// This typically is indicative of index corruption.
+	ErrIndexOutOfBounds = errors.New("index entry exceeds the bounds of its index node")
+
+	// ErrIndexEntryLengthInvalid is returned when an index entry has a length
+	// of zero.
+	//
+	// This typically is indicative of index corruption.
+	ErrIndexEntryLengthInvalid = errors.New("index entry has an invalid length")
+
+	// ErrInvalidIndexSignature is returned when an index record does not
+	// begin with the "INDX" signature.
+	ErrInvalidIndexSignature = errors.New("index record does not contain a valid signature")
+
+	// ErrInvalidIndexRecordSize is returned when an index root specifies an
+	// invalid index record size.
+	ErrInvalidIndexRecordSize = errors.New("index root specifies an invalid index record size")
+
+	// ErrFileNameLengthMismatch is returned when marshaling a file name whose
+	// length doesn't match the length of its value.
+	ErrFileNameLengthMismatch = errors.New("file name length does not match the length of its value")
+
+	// ErrNoReader is returned when attempting to read data for a file that
+	// was not retrieved from a Reader.
+	ErrNoReader = errors.New("the file was not retrieved from a volume reader")
+
+	// ErrFileNotFound is returned when a file cannot be found at a given
+	// path.
+	ErrFileNotFound = errors.New("file not found")
+
+	// ErrNotDirectory is returned when a path traverses a file that is not
+	// a directory.
+	ErrNotDirectory = errors.New("not a directory")
+
+	// ErrBadCluster is returned when attempting to read attribute data that
+	// is stored in a cluster recorded as bad in the $BadClus system file.
+	ErrBadCluster = errors.New("data is stored in a bad cluster")
+
+	// ErrBadClusterListUnavailable is recorded as a warning when the list of
+	// bad clusters cannot be read from the $BadClus system file.
+	ErrBadClusterListUnavailable = errors.New("the list of bad clusters is unavailable")
+
+	// ErrInvalidParent is returned when a file name refers to a parent
+	// directory that is not in use, is not a directory or has been reused.
+	ErrInvalidParent = errors.New("the parent directory is invalid")
+
+	// ErrPathLoop is returned when the parent directory references of a
+	// file form a loop.
+	ErrPathLoop = errors.New("the parent directory references form a loop")
+
+	// ErrNoFileName is returned when a file has no file name attribute.
+	ErrNoFileName = errors.New("the file has no file name attribute")
+
+	// ErrNoUSNJournal is returned when a volume does not have a USN change
+	// journal.
+	ErrNoUSNJournal = errors.New("the volume does not have a USN change journal")
+
+	// ErrUSNRecordVersionUnsupported is returned when a USN record has a
+	// major version other than 2 or 3.
+	ErrUSNRecordVersionUnsupported = errors.New("unsupported USN record version")
+
+	// ErrNoVolume is returned when attempting to read non-resident data,
+	// or anything else that is stored outside of the file records, from a
+	// standalone master file table that was opened without its volume.
+	ErrNoVolume = errors.New("the volume is unavailable to a standalone master file table")
+
+	// ErrRecordSizeUnknown is returned when the file record size of a
+	// standalone master file table cannot be determined.
+	ErrRecordSizeUnknown = errors.New("unable to determine the file record size")
 )
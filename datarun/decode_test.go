@@ -0,0 +1,61 @@
+package datarun
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		pairs []MappingPair
+	}{
+		{"Empty", "00", nil},
+		{"Single", "220001550d" + "00", []MappingPair{{Length: 0x100, Offset: 0xd55}}},
+		{"Sparse", "0110" + "00", []MappingPair{{Length: 0x10, Sparse: true}}},
+		{"SparseBetween", "110120" + "010f" + "110110" + "00", []MappingPair{
+			{Length: 1, Offset: 0x20},
+			{Length: 0x0f, Sparse: true},
+			{Length: 1, Offset: 0x30},
+		}},
+		{"NegativeOffset", "21040010" + "210400f8" + "00", []MappingPair{
+			{Length: 4, Offset: 0x1000},
+			{Length: 4, Offset: 0x800},
+		}},
+		{"NegativeOffsetAfterSparse", "21040001" + "0104" + "210410ff" + "00", []MappingPair{
+			{Length: 4, Offset: 0x100},
+			{Length: 4, Sparse: true},
+			{Length: 4, Offset: 0x10},
+		}},
+		{"Length80", "12800001" + "00", []MappingPair{{Length: 0x80, Offset: 1}}},
+		{"Length8000", "1300800001" + "00", []MappingPair{{Length: 0x8000, Offset: 1}}},
+		{"Offset7F", "11017f" + "00", []MappingPair{{Length: 1, Offset: 0x7f}}},
+		{"Offset80", "21018000" + "00", []MappingPair{{Length: 1, Offset: 0x80}}},
+		{"Offset8000", "3101008000" + "00", []MappingPair{{Length: 1, Offset: 0x8000}}},
+		{"OffsetMinus80", "21010001" + "110180" + "00", []MappingPair{
+			{Length: 1, Offset: 0x100},
+			{Length: 1, Offset: 0x80},
+		}},
+		{"OffsetMinus8001", "3101000001" + "3101ff7fff" + "00", []MappingPair{
+			{Length: 1, Offset: 0x10000},
+			{Length: 1, Offset: 0x7fff},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := hex.DecodeString(test.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pairs, err := Decode(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(pairs, test.pairs) {
+				t.Errorf("got %v, want %v", pairs, test.pairs)
+			}
+		})
+	}
+}
@@ -57,13 +57,16 @@ func (d *Decoder) Read() (MappingPair, error) {
 		result.Length = parseUint64(buf[0:lenBytes])
 	}
 
-	// Parse the offset and add it to the previous value
+	// Parse the offset and add it to the previous value. Runs without an
+	// offset are sparse.
 	offBytes := header.OffsetBytes()
 	if offBytes > 0 {
 		off1 := lenBytes
 		off2 := off1 + header.OffsetBytes()
 		d.offset += parseInt64(buf[off1:off2])
 		result.Offset = d.offset
+	} else {
+		result.Sparse = true
 	}
 
 	return result, nil
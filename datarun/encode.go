@@ -0,0 +1,23 @@
+package datarun
+
+import "bytes"
+
+// Encode encodes pairs as a series of mapping pairs followed by an
+// end-of-stream header. Each mapping pair is encoded with the fewest bytes
+// possible.
+//
+// The encoding is the inverse of Decode, such that Decode(Encode(pairs))
+// returns pairs for any slice of valid mapping pairs.
+func Encode(pairs []MappingPair) ([]byte, error) {
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	for _, pair := range pairs {
+		if err := e.Write(pair); err != nil {
+			return nil, err
+		}
+	}
+	if err := e.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
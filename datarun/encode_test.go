@@ -0,0 +1,117 @@
+package datarun
+
+import (
+	"bytes"
+	"encoding/hex"
+	"reflect"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name  string
+		pairs []MappingPair
+		data  string
+	}{
+		{"Empty", nil, "00"},
+		{"Single", []MappingPair{{Length: 0x100, Offset: 0xd55}}, "220001550d" + "00"},
+		{"Sparse", []MappingPair{{Length: 0x10, Sparse: true}}, "0110" + "00"},
+		{"SparseBetween", []MappingPair{
+			{Length: 1, Offset: 0x20},
+			{Length: 0x0f, Sparse: true},
+			{Length: 1, Offset: 0x30},
+		}, "110120" + "010f" + "110110" + "00"},
+		{"NegativeOffset", []MappingPair{
+			{Length: 4, Offset: 0x1000},
+			{Length: 4, Offset: 0x800},
+		}, "21040010" + "210400f8" + "00"},
+		{"NegativeOffsetAfterSparse", []MappingPair{
+			{Length: 4, Offset: 0x100},
+			{Length: 4, Sparse: true},
+			{Length: 4, Offset: 0x10},
+		}, "21040001" + "0104" + "210410ff" + "00"},
+		{"Length7F", []MappingPair{{Length: 0x7f, Offset: 1}}, "117f01" + "00"},
+		{"Length80", []MappingPair{{Length: 0x80, Offset: 1}}, "12800001" + "00"},
+		{"Length7FFF", []MappingPair{{Length: 0x7fff, Offset: 1}}, "12ff7f01" + "00"},
+		{"Length8000", []MappingPair{{Length: 0x8000, Offset: 1}}, "1300800001" + "00"},
+		{"Offset7F", []MappingPair{{Length: 1, Offset: 0x7f}}, "11017f" + "00"},
+		{"Offset80", []MappingPair{{Length: 1, Offset: 0x80}}, "21018000" + "00"},
+		{"Offset7FFF", []MappingPair{{Length: 1, Offset: 0x7fff}}, "2101ff7f" + "00"},
+		{"Offset8000", []MappingPair{{Length: 1, Offset: 0x8000}}, "3101008000" + "00"},
+		{"OffsetMinus80", []MappingPair{
+			{Length: 1, Offset: 0x100},
+			{Length: 1, Offset: 0x80},
+		}, "21010001" + "110180" + "00"},
+		{"OffsetMinus81", []MappingPair{
+			{Length: 1, Offset: 0x100},
+			{Length: 1, Offset: 0x7f},
+		}, "21010001" + "21017fff" + "00"},
+		{"OffsetMinus8000", []MappingPair{
+			{Length: 1, Offset: 0x10000},
+			{Length: 1, Offset: 0x8000},
+		}, "3101000001" + "21010080" + "00"},
+		{"OffsetMinus8001", []MappingPair{
+			{Length: 1, Offset: 0x10000},
+			{Length: 1, Offset: 0x7fff},
+		}, "3101000001" + "3101ff7fff" + "00"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := Encode(test.pairs)
+			if err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+			if got := hex.EncodeToString(data); got != test.data {
+				t.Errorf("encode: got %s, want %s", got, test.data)
+			}
+			pairs, err := Decode(data)
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if !reflect.DeepEqual(pairs, test.pairs) {
+				t.Errorf("decode: got %v, want %v", pairs, test.pairs)
+			}
+		})
+	}
+}
+
+func TestEncodeInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		pair MappingPair
+		err  error
+	}{
+		{"ZeroLength", MappingPair{Length: 0, Offset: 1}, ErrInvalidLength},
+		{"LongLength", MappingPair{Length: 1 << 63, Offset: 1}, ErrInvalidLength},
+		{"SparseOffset", MappingPair{Length: 1, Offset: 1, Sparse: true}, ErrSparseOffset},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := Encode([]MappingPair{test.pair}); err != test.err {
+				t.Errorf("got %v, want %v", err, test.err)
+			}
+		})
+	}
+}
+
+// The mapping pairs of a compressed file on a volume formatted by Windows,
+// with each compression unit of 16 clusters stored in a single cluster.
+const testWindowsMappingPairs = "2101a604010f110110010f110110010f110110010f110110010f110110010f110110010f110106010f110110010f110110010f110106010f110110010f21011e11010f00"
+
+func TestEncodeWindows(t *testing.T) {
+	data, _ := hex.DecodeString(testWindowsMappingPairs)
+	pairs, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 26 {
+		t.Fatalf("decode: got %d mapping pairs, want 26", len(pairs))
+	}
+	out, err := Encode(pairs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, data) {
+		t.Errorf("encode:\n got %x\nwant %x", out, data)
+	}
+}
@@ -0,0 +1,70 @@
+package datarun
+
+import "io"
+
+// https://msdn.microsoft.com/library/bb470039
+
+// Encoder encodes a series of data run mapping pairs and writes them to an
+// underlying writer.
+type Encoder struct {
+	w      io.Writer
+	offset int64
+	done   bool
+}
+
+// NewEncoder returns a new encoder that writes to w. The caller must call
+// Close after writing the last mapping pair so that the end-of-stream header
+// is written.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Write encodes pair and writes it to the underlying writer.
+//
+// The offset of pair is an absolute logical cluster number. It is encoded
+// relative to the offset of the previous non-sparse mapping pair using the
+// fewest bytes possible. Sparse mapping pairs are encoded without an offset
+// and must have an offset of zero.
+func (e *Encoder) Write(pair MappingPair) error {
+	if e.done {
+		return ErrEncoderClosed
+	}
+	if pair.Length == 0 || pair.Length > maxInt64 {
+		return ErrInvalidLength
+	}
+	if pair.Sparse && pair.Offset != 0 {
+		return ErrSparseOffset
+	}
+
+	var buf [17]byte
+
+	// Encode the length
+	lenBytes := putInt64(buf[1:], int64(pair.Length))
+
+	// Encode the offset relative to the previous value
+	offBytes := 0
+	if !pair.Sparse {
+		offBytes = putInt64(buf[1+lenBytes:], pair.Offset-e.offset)
+	}
+
+	buf[0] = byte(makeHeader(lenBytes, offBytes))
+	if _, err := e.w.Write(buf[0 : 1+lenBytes+offBytes]); err != nil {
+		return err
+	}
+
+	if !pair.Sparse {
+		e.offset = pair.Offset
+	}
+	return nil
+}
+
+// Close writes the end-of-stream header to the underlying writer. It does
+// not close the underlying writer.
+func (e *Encoder) Close() error {
+	if e.done {
+		return nil
+	}
+	e.done = true
+	_, err := e.w.Write([]byte{byte(EOF)})
+	return err
+}
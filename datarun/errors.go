@@ -10,4 +10,24 @@ var (
 	// ErrInvalidHeader is returned when attempting to decode a data run
 	// header that has an invalid size.
 	ErrInvalidHeader = errors.New("invalid data run header")
+
+	// ErrInvalidLength is returned when attempting to encode a mapping pair
+	// with a length of zero or a length that exceeds a signed 64-bit integer.
+	ErrInvalidLength = errors.New("invalid data run length")
+
+	// ErrSparseOffset is returned when attempting to encode a sparse mapping
+	// pair with a non-zero offset.
+	ErrSparseOffset = errors.New("sparse data run has a non-zero offset")
+
+	// ErrEncoderClosed is returned when attempting to write a mapping pair
+	// to an encoder that has been closed.
+	ErrEncoderClosed = errors.New("data run encoder has been closed")
+
+	// ErrRunlistMismatch is returned when the data runs of an attribute
+	// segment don't cover the range of virtual clusters it claims to map.
+	ErrRunlistMismatch = errors.New("data runs do not match the virtual cluster range of their segment")
+
+	// ErrOverlappingRuns is returned when merging runlists that map the
+	// same virtual clusters.
+	ErrOverlappingRuns = errors.New("data runs overlap")
 )
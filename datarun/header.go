@@ -3,6 +3,12 @@ package datarun
 // Header describes the allocation of bytes in a mapping pair.
 type Header byte
 
+// makeHeader returns a header describing a mapping pair with the given
+// number of length and offset bytes.
+func makeHeader(lengthBytes, offsetBytes int) Header {
+	return Header(offsetBytes<<4 | lengthBytes)
+}
+
 // LengthBytes returns the number of length bytes in a mapping pair.
 func (h Header) LengthBytes() int {
 	return int(h & 0x0f) // Lower nibble
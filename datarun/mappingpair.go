@@ -4,5 +4,6 @@ package datarun
 // so that it can be mapped to a virtual cluster number.
 type MappingPair struct {
 	Length uint64 // In clusters
-	Offset int64  // Relative to previous data run, may be negative
+	Offset int64  // Logical cluster number, accumulated from the relative offsets of preceding data runs
+	Sparse bool   // True if the run has no offset and isn't backed by any clusters
 }
@@ -0,0 +1,170 @@
+package datarun
+
+import "sort"
+
+// Extent is a contiguous run of virtual clusters and the logical clusters
+// that back it.
+type Extent struct {
+	VCN    uint64 // The first virtual cluster number of the run
+	LCN    int64  // The first logical cluster number of the run, zero if sparse
+	Length uint64 // In clusters
+	Sparse bool   // True if the run isn't backed by any clusters
+}
+
+// End returns the virtual cluster number that follows the extent.
+func (e Extent) End() uint64 {
+	return e.VCN + e.Length
+}
+
+// Contains returns true if vcn falls within the extent.
+func (e Extent) Contains(vcn uint64) bool {
+	return vcn >= e.VCN && vcn < e.End()
+}
+
+// Runlist is a sorted list of non-overlapping extents that maps virtual
+// cluster numbers to logical cluster numbers. Virtual clusters that aren't
+// covered by any extent are unmapped.
+type Runlist []Extent
+
+// NewRunlist returns a runlist for a series of mapping pairs that map
+// virtual clusters starting at lowest.
+func NewRunlist(lowest uint64, pairs []MappingPair) Runlist {
+	rl := make(Runlist, 0, len(pairs))
+	vcn := lowest
+	for _, pair := range pairs {
+		rl = append(rl, Extent{
+			VCN:    vcn,
+			LCN:    pair.Offset,
+			Length: pair.Length,
+			Sparse: pair.Sparse,
+		})
+		vcn += pair.Length
+	}
+	return rl
+}
+
+// DecodeRunlist decodes the mapping pairs of an attribute segment that maps
+// virtual clusters lowest through highest. If the decoded data runs don't
+// cover exactly that range ErrRunlistMismatch is returned.
+func DecodeRunlist(data []byte, lowest, highest uint64) (Runlist, error) {
+	pairs, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	rl := NewRunlist(lowest, pairs)
+	if len(rl) > 0 && rl[len(rl)-1].End() != highest+1 {
+		return rl, ErrRunlistMismatch
+	}
+	return rl, nil
+}
+
+// Merge combines the runlists of several attribute segments into a single
+// runlist. The runlists may be provided in any order. If any of their
+// extents overlap ErrOverlappingRuns is returned.
+func Merge(lists ...Runlist) (Runlist, error) {
+	var total int
+	for _, rl := range lists {
+		total += len(rl)
+	}
+	merged := make(Runlist, 0, total)
+	for _, rl := range lists {
+		merged = append(merged, rl...)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].VCN < merged[j].VCN
+	})
+	for i := 1; i < len(merged); i++ {
+		if merged[i].VCN < merged[i-1].End() {
+			return nil, ErrOverlappingRuns
+		}
+	}
+	return merged, nil
+}
+
+// Find returns the index of the extent that contains vcn. If vcn is
+// unmapped it returns -1.
+func (rl Runlist) Find(vcn uint64) int {
+	i := sort.Search(len(rl), func(i int) bool {
+		return rl[i].End() > vcn
+	})
+	if i < len(rl) && rl[i].Contains(vcn) {
+		return i
+	}
+	return -1
+}
+
+// Lookup returns the logical cluster number that backs vcn. If vcn falls
+// within a sparse run sparse will be true. If vcn is unmapped ok will be
+// false.
+func (rl Runlist) Lookup(vcn uint64) (lcn int64, sparse, ok bool) {
+	i := rl.Find(vcn)
+	if i < 0 {
+		return 0, false, false
+	}
+	e := rl[i]
+	if e.Sparse {
+		return 0, true, true
+	}
+	return e.LCN + int64(vcn-e.VCN), false, true
+}
+
+// Range returns the extents that map length virtual clusters starting at
+// vcn, trimmed to that range. Unmapped clusters within the range are
+// omitted.
+func (rl Runlist) Range(vcn, length uint64) Runlist {
+	if length == 0 {
+		return nil
+	}
+	end := vcn + length
+	i := sort.Search(len(rl), func(i int) bool {
+		return rl[i].End() > vcn
+	})
+	var result Runlist
+	for ; i < len(rl) && rl[i].VCN < end; i++ {
+		e := rl[i]
+		if e.VCN < vcn {
+			if !e.Sparse {
+				e.LCN += int64(vcn - e.VCN)
+			}
+			e.Length -= vcn - e.VCN
+			e.VCN = vcn
+		}
+		if e.End() > end {
+			e.Length = end - e.VCN
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+// Clusters returns the number of virtual clusters mapped by the runlist,
+// including sparse clusters.
+func (rl Runlist) Clusters() (n uint64) {
+	for _, e := range rl {
+		n += e.Length
+	}
+	return
+}
+
+// Allocated returns the number of logical clusters that back the runlist.
+func (rl Runlist) Allocated() (n uint64) {
+	for _, e := range rl {
+		if !e.Sparse {
+			n += e.Length
+		}
+	}
+	return
+}
+
+// Pairs returns the mapping pairs of the runlist, suitable for encoding.
+func (rl Runlist) Pairs() []MappingPair {
+	pairs := make([]MappingPair, 0, len(rl))
+	for _, e := range rl {
+		pairs = append(pairs, MappingPair{
+			Length: e.Length,
+			Offset: e.LCN,
+			Sparse: e.Sparse,
+		})
+	}
+	return pairs
+}
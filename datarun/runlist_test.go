@@ -0,0 +1,157 @@
+package datarun
+
+import (
+	"reflect"
+	"testing"
+)
+
+// testRunlist maps virtual clusters 0-9 and 20-39, with 10-19 unmapped and
+// 25-29 sparse.
+var testRunlist = Runlist{
+	{VCN: 0, LCN: 100, Length: 10},
+	{VCN: 20, LCN: 500, Length: 5},
+	{VCN: 25, Length: 5, Sparse: true},
+	{VCN: 30, LCN: 50, Length: 10},
+}
+
+func TestRunlistFind(t *testing.T) {
+	tests := []struct {
+		vcn  uint64
+		want int
+	}{
+		{0, 0},
+		{9, 0},
+		{10, -1},
+		{19, -1},
+		{20, 1},
+		{24, 1},
+		{25, 2},
+		{29, 2},
+		{30, 3},
+		{39, 3},
+		{40, -1},
+		{1 << 40, -1},
+	}
+	for _, test := range tests {
+		if got := testRunlist.Find(test.vcn); got != test.want {
+			t.Errorf("Find(%d): got %d, want %d", test.vcn, got, test.want)
+		}
+	}
+	if got := Runlist(nil).Find(0); got != -1 {
+		t.Errorf("Find on empty runlist: got %d, want -1", got)
+	}
+}
+
+func TestRunlistLookup(t *testing.T) {
+	tests := []struct {
+		vcn    uint64
+		lcn    int64
+		sparse bool
+		ok     bool
+	}{
+		{0, 100, false, true},
+		{9, 109, false, true},
+		{15, 0, false, false},
+		{22, 502, false, true},
+		{27, 0, true, true},
+		{35, 55, false, true},
+		{40, 0, false, false},
+	}
+	for _, test := range tests {
+		lcn, sparse, ok := testRunlist.Lookup(test.vcn)
+		if lcn != test.lcn || sparse != test.sparse || ok != test.ok {
+			t.Errorf("Lookup(%d): got (%d, %t, %t), want (%d, %t, %t)", test.vcn, lcn, sparse, ok, test.lcn, test.sparse, test.ok)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name  string
+		lists []Runlist
+		want  Runlist
+		err   error
+	}{
+		{"Empty", nil, Runlist{}, nil},
+		{"Single", []Runlist{testRunlist}, testRunlist, nil},
+		{"InOrder", []Runlist{testRunlist[:2], testRunlist[2:]}, testRunlist, nil},
+		{"OutOfOrder", []Runlist{testRunlist[3:], testRunlist[:1], testRunlist[1:3]}, testRunlist, nil},
+		{"Adjacent", []Runlist{
+			{{VCN: 0, LCN: 10, Length: 5}},
+			{{VCN: 5, LCN: 10, Length: 5}},
+		}, Runlist{
+			{VCN: 0, LCN: 10, Length: 5},
+			{VCN: 5, LCN: 10, Length: 5},
+		}, nil},
+		{"Overlapping", []Runlist{
+			{{VCN: 0, LCN: 10, Length: 5}},
+			{{VCN: 4, LCN: 20, Length: 5}},
+		}, nil, ErrOverlappingRuns},
+		{"Duplicate", []Runlist{testRunlist, testRunlist[1:2]}, nil, ErrOverlappingRuns},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Merge(test.lists...)
+			if err != test.err {
+				t.Fatalf("got error %v, want %v", err, test.err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRunlistRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		vcn    uint64
+		length uint64
+		want   Runlist
+	}{
+		{"All", 0, 40, testRunlist},
+		{"Beyond", 0, 100, testRunlist},
+		{"WithinExtent", 2, 5, Runlist{{VCN: 2, LCN: 102, Length: 5}}},
+		{"Unmapped", 10, 10, nil},
+		{"AfterEnd", 40, 10, nil},
+		{"ZeroLength", 5, 0, nil},
+		{"AcrossGap", 8, 14, Runlist{
+			{VCN: 8, LCN: 108, Length: 2},
+			{VCN: 20, LCN: 500, Length: 2},
+		}},
+		{"StartInSparse", 27, 5, Runlist{
+			{VCN: 27, Length: 3, Sparse: true},
+			{VCN: 30, LCN: 50, Length: 2},
+		}},
+		{"EndInSparse", 22, 5, Runlist{
+			{VCN: 22, LCN: 502, Length: 3},
+			{VCN: 25, Length: 2, Sparse: true},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := testRunlist.Range(test.vcn, test.length); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDecodeRunlist(t *testing.T) {
+	data := []byte{0x11, 0x04, 0x10, 0x01, 0x04, 0x11, 0x02, 0x10, 0x00}
+	want := Runlist{
+		{VCN: 8, LCN: 0x10, Length: 4},
+		{VCN: 12, Length: 4, Sparse: true},
+		{VCN: 16, LCN: 0x20, Length: 2},
+	}
+	rl, err := DecodeRunlist(data, 8, 17)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rl, want) {
+		t.Errorf("got %v, want %v", rl, want)
+	}
+	if _, err := DecodeRunlist(data, 8, 20); err != ErrRunlistMismatch {
+		t.Errorf("mismatched range: got %v, want %v", err, ErrRunlistMismatch)
+	}
+}
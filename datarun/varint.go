@@ -1,16 +1,35 @@
 package datarun
 
 func parseUint64(data []byte) (value uint64) {
-	for i, k := uint(0), uint(len(data)); i < k; i++ {
-		value |= uint64(data[i]) << i * 8
+	for i := len(data) - 1; i >= 0; i-- {
+		value = value<<8 | uint64(data[i])
 	}
 	return
 }
 
-func parseInt64(data []byte) (value int64) {
-	// FIXME: Preserve sign?
-	for i, k := uint(0), uint(len(data)); i < k; i++ {
-		value |= int64(data[i]) << i * 8
+func parseInt64(data []byte) int64 {
+	value := parseUint64(data)
+	// Sign-extend values that don't fill all 64 bits
+	if n := uint(len(data)); n > 0 && n < 8 && data[n-1]&0x80 != 0 {
+		value |= ^uint64(0) << (n * 8)
+	}
+	return int64(value)
+}
+
+const maxInt64 = 1<<63 - 1
+
+// putInt64 writes the little-endian two's complement representation of
+// value to data using the fewest bytes that preserve its sign. It returns
+// the number of bytes written.
+//
+// The provided data must be at least 8 bytes long.
+func putInt64(data []byte, value int64) (n int) {
+	for {
+		data[n] = byte(value)
+		n++
+		value >>= 8
+		if (value == 0 && data[n-1]&0x80 == 0) || (value == -1 && data[n-1]&0x80 != 0) {
+			return n
+		}
 	}
-	return
 }
@@ -0,0 +1,307 @@
+// Package dfxml writes descriptions of NTFS volumes in the Digital
+// Forensics XML format.
+//
+// https://github.com/dfxml-working-group/dfxml_schema
+package dfxml
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"hash"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gentlemanautomaton/ntfs"
+	"github.com/gentlemanautomaton/ntfs/attrtype"
+	"github.com/gentlemanautomaton/ntfs/fileattr"
+)
+
+// Namespace is the XML namespace of DFXML documents.
+const Namespace = "http://www.forensicswiki.org/wiki/Category:Digital_Forensics_XML"
+
+// Version is the DFXML schema version of written documents.
+const Version = "1.2.0"
+
+// Meta types of file objects.
+const (
+	MetaTypeRegular   = 1
+	MetaTypeDirectory = 2
+)
+
+// Options control the content of a DFXML document.
+type Options struct {
+	Program       string   // The name of the program creating the document
+	ImageFilename string   // The name of the image holding the volume
+	ImageOffset   int64    // The offset of the volume within the image in bytes
+	Hashes        []string // Hash digests to compute: md5, sha1 or sha256
+
+	// RecordError is called by Write for each file record that can't be
+	// read or described, and so has no file object. Records that have
+	// never been used aren't reported. It may be nil.
+	RecordError func(id int64, err error)
+}
+
+// Volume describes an NTFS volume.
+type Volume struct {
+	XMLName          xml.Name `xml:"volume"`
+	Offset           int64    `xml:"offset,attr"`
+	PartitionOffset  int64    `xml:"partition_offset"`
+	SectorSize       int64    `xml:"sector_size"`
+	BlockSize        int64    `xml:"block_size"`
+	FileSystemType   string   `xml:"ftype_str"`
+	BlockCount       int64    `xml:"block_count"`
+	FirstBlock       int64    `xml:"first_block"`
+	LastBlock        int64    `xml:"last_block"`
+	SerialNumber     uint64   `xml:"volume_serial"`
+	MFTCluster       uint64   `xml:"mft_lcn"`
+	MFTMirrorCluster uint64   `xml:"mftmirr_lcn"`
+	FileRecordSize   int64    `xml:"file_record_size"`
+	IndexBlockSize   int64    `xml:"index_block_size"`
+}
+
+// FileObject describes a file record.
+type FileObject struct {
+	XMLName    xml.Name     `xml:"fileobject"`
+	Parent     *ParentInode `xml:"parent_object,omitempty"`
+	Filename   string       `xml:"filename"`
+	NameType   string       `xml:"name_type"`
+	FileSize   int64        `xml:"filesize"`
+	Alloc      int          `xml:"alloc"`
+	Unalloc    int          `xml:"unalloc,omitempty"`
+	Inode      int64        `xml:"inode"`
+	MetaType   int          `xml:"meta_type"`
+	Mode       int          `xml:"mode"`
+	NLink      uint16       `xml:"nlink"`
+	UID        int          `xml:"uid"`
+	GID        int          `xml:"gid"`
+	MTime      *Timestamp   `xml:"mtime,omitempty"`
+	CTime      *Timestamp   `xml:"ctime,omitempty"`
+	ATime      *Timestamp   `xml:"atime,omitempty"`
+	CRTime     *Timestamp   `xml:"crtime,omitempty"`
+	Sequence   uint16       `xml:"seq"`
+	ByteRuns   *ByteRuns    `xml:"byte_runs,omitempty"`
+	HashDigest []HashDigest `xml:"hashdigest,omitempty"`
+}
+
+// ParentInode identifies the parent directory of a file object.
+type ParentInode struct {
+	Inode int64 `xml:"inode"`
+}
+
+// Timestamp is a file object timestamp.
+type Timestamp struct {
+	Prec  string `xml:"prec,attr"`
+	Value string `xml:",chardata"`
+}
+
+// ByteRuns lists the locations of a file's data.
+type ByteRuns struct {
+	Runs []ByteRun `xml:"byte_run"`
+}
+
+// ByteRun describes a contiguous run of file data. Sparse runs have a
+// fill value instead of an offset. Resident data has a type of "resident"
+// and data stored in compressed form has a type of "compressed".
+type ByteRun struct {
+	FileOffset int64  `xml:"file_offset,attr"`
+	FSOffset   *int64 `xml:"fs_offset,attr,omitempty"`
+	ImgOffset  *int64 `xml:"img_offset,attr,omitempty"`
+	Length     int64  `xml:"len,attr"`
+	Fill       *int   `xml:"fill,attr,omitempty"`
+	Type       string `xml:"type,attr,omitempty"`
+}
+
+// HashDigest is a hash of a file's data.
+type HashDigest struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// NewVolume returns a description of the volume read by r.
+func NewVolume(r *ntfs.Reader, opts Options) Volume {
+	boot := r.BootRecord()
+	clusters := boot.Clusters()
+	return Volume{
+		Offset:           opts.ImageOffset,
+		PartitionOffset:  opts.ImageOffset,
+		SectorSize:       int64(boot.BytesPerSector),
+		BlockSize:        int64(boot.ClusterSize()),
+		FileSystemType:   "ntfs",
+		BlockCount:       clusters,
+		FirstBlock:       0,
+		LastBlock:        clusters - 1,
+		SerialNumber:     boot.VolumeSerialNumber,
+		MFTCluster:       boot.MFT,
+		MFTMirrorCluster: boot.MFTMirror,
+		FileRecordSize:   int64(boot.FileRecordSize()),
+		IndexBlockSize:   int64(boot.IndexBlockSize()),
+	}
+}
+
+// NewFileObject returns a description of file, which must have been
+// retrieved from r. Its path is resolved by resolver.
+func NewFileObject(r *ntfs.Reader, resolver *ntfs.PathResolver, file *ntfs.File, opts Options) (*FileObject, error) {
+	header := &file.Header
+	obj := &FileObject{
+		NameType: "r",
+		Inode:    file.ID,
+		MetaType: MetaTypeRegular,
+		Mode:     0777,
+		NLink:    header.HardLinkCount,
+		Sequence: header.SequenceNumber,
+	}
+	if header.InUse() {
+		obj.Alloc = 1
+	} else {
+		obj.Alloc, obj.Unalloc = 0, 1
+	}
+	if header.Directory() {
+		obj.NameType = "d"
+		obj.MetaType = MetaTypeDirectory
+	}
+
+	if path, err := resolver.Path(file); err == nil {
+		obj.Filename = strings.TrimPrefix(strings.Replace(path, ntfs.PathSeparator, "/", -1), "/")
+	}
+	names, err := r.Names(file)
+	if err != nil {
+		return nil, err
+	}
+	if name, ok := ntfs.PreferredName(names); ok {
+		obj.Parent = &ParentInode{Inode: name.ParentDirectory.SegmentNumber()}
+	}
+
+	if attr, ok := file.Attribute(attrtype.StandardInformation); ok {
+		var si ntfs.StandardInformation
+		if err := si.UnmarshalBinary(attr.ResidentValue); err == nil {
+			obj.MTime = newTimestamp(si.FileModification)
+			obj.CTime = newTimestamp(si.MFTModification)
+			obj.ATime = newTimestamp(si.FileRead)
+			obj.CRTime = newTimestamp(si.FileCreation)
+			if si.DOSFilePermissions&fileattr.ReadOnly != 0 {
+				obj.Mode = 0555
+			}
+		}
+	}
+
+	if header.Directory() {
+		return obj, nil
+	}
+	streams, err := r.Streams(file)
+	if err != nil {
+		return obj, nil
+	}
+	for _, stream := range streams {
+		if stream.Name != "" {
+			continue
+		}
+		obj.FileSize = stream.Size
+		obj.ByteRuns = byteRuns(r, file, stream, opts)
+		if len(opts.Hashes) > 0 {
+			obj.HashDigest = hashStream(r, file, opts.Hashes)
+		}
+	}
+	return obj, nil
+}
+
+// byteRuns returns the byte runs of the unnamed $DATA stream of file.
+func byteRuns(r *ntfs.Reader, file *ntfs.File, stream ntfs.Stream, opts Options) *ByteRuns {
+	if stream.Resident {
+		return &ByteRuns{Runs: []ByteRun{{Length: stream.Size, Type: "resident"}}}
+	}
+	extents, err := r.Extents(file, attrtype.Data, "")
+	if err != nil {
+		return nil
+	}
+	var (
+		runs       []ByteRun
+		compressed bool
+	)
+	for _, e := range extents {
+		if e.Offset >= stream.Size {
+			break
+		}
+		length := e.Length
+		if e.Offset+length > stream.Size {
+			length = stream.Size - e.Offset
+		}
+		run := ByteRun{FileOffset: e.Offset, Length: length}
+		switch {
+		case e.Sparse && compressed:
+			// The rest of a compression unit stored in compressed form
+			continue
+		case e.Sparse:
+			fill := 0
+			run.Fill = &fill
+		default:
+			fs, img := e.PhysicalOffset, e.PhysicalOffset+opts.ImageOffset
+			run.FSOffset, run.ImgOffset = &fs, &img
+			if e.Compressed {
+				run.Type = "compressed"
+			}
+		}
+		compressed = e.Compressed
+		runs = append(runs, run)
+	}
+	if len(runs) == 0 {
+		return nil
+	}
+	return &ByteRuns{Runs: runs}
+}
+
+// hashStream returns the requested hash digests of the unnamed $DATA
+// stream of file. If the stream can't be read no digests are returned.
+func hashStream(r *ntfs.Reader, file *ntfs.File, types []string) []HashDigest {
+	stream, err := r.OpenStream(file, attrtype.Data, "")
+	if err != nil {
+		return nil
+	}
+	var (
+		hashes  []hash.Hash
+		writers []io.Writer
+		names   []string
+	)
+	for _, t := range types {
+		h := newHash(t)
+		if h == nil {
+			continue
+		}
+		hashes = append(hashes, h)
+		writers = append(writers, h)
+		names = append(names, strings.ToLower(t))
+	}
+	if _, err := io.Copy(io.MultiWriter(writers...), stream); err != nil {
+		return nil
+	}
+	digests := make([]HashDigest, len(hashes))
+	for i, h := range hashes {
+		digests[i] = HashDigest{Type: names[i], Value: hex.EncodeToString(h.Sum(nil))}
+	}
+	return digests
+}
+
+// newHash returns a new hash of the given type, or nil if the type isn't
+// supported.
+func newHash(t string) hash.Hash {
+	switch strings.ToLower(t) {
+	case "md5":
+		return md5.New()
+	case "sha1":
+		return sha1.New()
+	case "sha256":
+		return sha256.New()
+	}
+	return nil
+}
+
+// newTimestamp returns t as a DFXML timestamp, or nil if t is zero.
+func newTimestamp(t time.Time) *Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return &Timestamp{Prec: "100ns", Value: t.UTC().Format(time.RFC3339Nano)}
+}
@@ -0,0 +1,9 @@
+package dfxml
+
+import "errors"
+
+var (
+	// ErrUnknownHash is returned when a hash digest that isn't supported is
+	// requested.
+	ErrUnknownHash = errors.New("unknown hash digest type")
+)
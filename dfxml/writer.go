@@ -0,0 +1,160 @@
+package dfxml
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/gentlemanautomaton/ntfs"
+)
+
+// Write writes a DFXML document describing the volume read by r to w. A
+// file object is written for every file record that can be read,
+// including records that are no longer in use. Records that can't be
+// read are reported to opts.RecordError.
+//
+// If opts.Hashes includes an unknown hash digest type ErrUnknownHash is
+// returned before anything is written.
+//
+// File objects are streamed as they are produced, so memory use doesn't
+// grow with the number of records.
+func Write(w io.Writer, r *ntfs.Reader, opts Options) error {
+	for _, t := range opts.Hashes {
+		if newHash(t) == nil {
+			return fmt.Errorf("%w: %s", ErrUnknownHash, t)
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	root := xml.StartElement{
+		Name: xml.Name{Local: "dfxml"},
+		Attr: []xml.Attr{
+			{Name: xml.Name{Local: "xmlns"}, Value: Namespace},
+			{Name: xml.Name{Local: "xmlns:dc"}, Value: "http://purl.org/dc/elements/1.1/"},
+			{Name: xml.Name{Local: "version"}, Value: Version},
+		},
+	}
+	if err := enc.EncodeToken(root); err != nil {
+		return err
+	}
+
+	metadata := struct {
+		XMLName xml.Name `xml:"metadata"`
+		Type    string   `xml:"dc:type"`
+	}{Type: "Disk Image"}
+	if err := enc.Encode(metadata); err != nil {
+		return err
+	}
+	if opts.Program != "" {
+		creator := struct {
+			XMLName xml.Name `xml:"creator"`
+			Program string   `xml:"program"`
+		}{Program: opts.Program}
+		if err := enc.Encode(creator); err != nil {
+			return err
+		}
+	}
+	if opts.ImageFilename != "" {
+		source := struct {
+			XMLName       xml.Name `xml:"source"`
+			ImageFilename string   `xml:"image_filename"`
+		}{ImageFilename: opts.ImageFilename}
+		if err := enc.Encode(source); err != nil {
+			return err
+		}
+	}
+
+	// The volume is written element by element so that its file objects
+	// can be streamed
+	volume := NewVolume(r, opts)
+	start, elements := volumeElements(&volume)
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, element := range elements {
+		if err := enc.EncodeElement(element.value, element.start); err != nil {
+			return err
+		}
+	}
+
+	resolver := r.NewPathResolver()
+	id := int64(-1)
+	err := r.Walk(func(file *ntfs.File, err error) error {
+		id++
+		if errors.Is(err, ntfs.ErrUnusedRecord) {
+			return nil
+		}
+		if err != nil {
+			opts.recordError(id, err)
+			return nil
+		}
+		if !file.Header.BaseFileRecordSegment.IsZero() {
+			return nil
+		}
+		obj, err := NewFileObject(r, resolver, file, opts)
+		if err != nil {
+			opts.recordError(id, err)
+			return nil
+		}
+		return enc.Encode(obj)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := enc.EncodeToken(start.End()); err != nil {
+		return err
+	}
+	if err := enc.EncodeToken(root.End()); err != nil {
+		return err
+	}
+	if err := enc.Flush(); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+type volumeElement struct {
+	start xml.StartElement
+	value interface{}
+}
+
+// volumeElements returns the start element of volume and its child
+// elements, as described by the struct tags of Volume.
+func volumeElements(volume *Volume) (start xml.StartElement, elements []volumeElement) {
+	v := reflect.ValueOf(volume).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("xml"), ",")
+		name := xml.Name{Local: tag[0]}
+		switch {
+		case field.Name == "XMLName":
+			start.Name = name
+		case len(tag) > 1 && tag[1] == "attr":
+			start.Attr = append(start.Attr, xml.Attr{Name: name, Value: fmt.Sprint(v.Field(i).Interface())})
+		default:
+			elements = append(elements, volumeElement{
+				start: xml.StartElement{Name: name},
+				value: v.Field(i).Interface(),
+			})
+		}
+	}
+	return start, elements
+}
+
+// recordError reports a file record that can't be described.
+func (opts *Options) recordError(id int64, err error) {
+	if opts.RecordError != nil {
+		opts.RecordError(id, err)
+	}
+}
@@ -0,0 +1,238 @@
+// Package disk discovers the partitions of whole-disk images.
+//
+// Master boot record, GUID partition table and hybrid layouts are
+// supported, including logical partitions within MBR extended partitions.
+package disk
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gentlemanautomaton/ntfs/mspart"
+)
+
+// Sector sizes that are probed when detecting the layout of a disk.
+var sectorSizes = []int64{512, 4096}
+
+// Scheme identifies the partitioning scheme of a disk.
+type Scheme int
+
+// Partitioning schemes.
+const (
+	None   Scheme = iota // No partition table
+	MBR                  // Master boot record
+	GPT                  // GUID partition table with a protective MBR
+	Hybrid               // GUID partition table with a hybrid MBR
+)
+
+// String returns a string representation of the scheme.
+func (s Scheme) String() string {
+	switch s {
+	case None:
+		return "None"
+	case MBR:
+		return "MBR"
+	case GPT:
+		return "GPT"
+	case Hybrid:
+		return "Hybrid"
+	default:
+		return fmt.Sprintf("Scheme %d", int(s))
+	}
+}
+
+// Layout describes the partitions of a disk.
+type Layout struct {
+	Scheme     Scheme
+	SectorSize int64
+	DiskGUID   GUID // GPT only
+
+	// Partitions holds the partitions of the disk. For GPT and hybrid
+	// disks these come from the GUID partition table. For MBR disks they
+	// include the logical partitions of any extended partitions.
+	Partitions []Partition
+
+	// MBR holds the partitions listed in the master boot record, including
+	// the protective entry of GPT disks and any logical partitions.
+	MBR []Partition
+}
+
+// Partition describes a partition on a disk.
+type Partition struct {
+	Number     int    // 1-based; MBR logical partitions are numbered from 5
+	Scheme     Scheme // MBR or GPT
+	Offset     int64  // Offset from the start of the disk in bytes
+	Length     int64  // Length in bytes
+	MBRType    mspart.MBR
+	GPTType    GUID
+	GUID       GUID   // GPT only
+	Name       string // GPT only
+	Attributes uint64 // GPT only
+	Bootable   bool   // MBR only
+	Logical    bool   // MBR only; within an extended partition
+}
+
+// TypeName returns a description of the partition type.
+func (p Partition) TypeName() string {
+	if p.Scheme != GPT {
+		return p.MBRType.String()
+	}
+	if name := mspart.GPTName(p.GPTType); name != "" {
+		return name
+	}
+	return p.GPTType.String()
+}
+
+// MayContainNTFS returns true if the partition type is one that Windows
+// formats with NTFS.
+func (p Partition) MayContainNTFS() bool {
+	if p.Scheme == GPT {
+		return p.GPTType == mspart.BasicData || p.GPTType == mspart.RecoveryEnvironment
+	}
+	switch p.MBRType {
+	case mspart.IFS, mspart.HiddenIFS, mspart.WindowsRecovery:
+		return true
+	}
+	return false
+}
+
+// Open returns a reader for the contents of the partition within disk.
+// The returned reader can be passed to ntfs.NewReader.
+func (p Partition) Open(disk io.ReaderAt) *io.SectionReader {
+	return io.NewSectionReader(disk, p.Offset, p.Length)
+}
+
+// String returns a string representation of the partition.
+func (p Partition) String() string {
+	return fmt.Sprintf("%s partition %d: %s [%d:%d]", p.Scheme, p.Number, p.TypeName(), p.Offset, p.Offset+p.Length)
+}
+
+// Read reads the partition layout of a disk image of the given size.
+//
+// The sector size is detected from the location of the GUID partition
+// table header. If the primary GPT header is damaged the backup header at
+// the end of the disk is used instead. Disks with only a master boot record
+// are assumed to use 512-byte sectors unless their partitions only make
+// sense with 4096-byte sectors.
+//
+// If the disk has no partition table ErrNoPartitionTable is returned.
+func Read(r io.ReaderAt, size int64) (*Layout, error) {
+	var boot [mbrLength]byte
+	if _, err := r.ReadAt(boot[:], 0); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrNoPartitionTable
+		}
+		return nil, fmt.Errorf("failed to read master boot record: %w", err)
+	}
+	entries, ok := parseMBR(boot[:])
+	if !ok || !plausibleMBR(entries, size) {
+		return nil, ErrNoPartitionTable
+	}
+
+	protective := false
+	for _, entry := range entries {
+		if entry.Type == mspart.ProtectiveMBR {
+			protective = true
+		}
+	}
+
+	// Look for a GUID partition table
+	var gptErr error
+	if protective {
+		for _, sectorSize := range sectorSizes {
+			table, err := readGPT(r, size, sectorSize)
+			if err == nil {
+				return gptLayout(r, size, sectorSize, table, entries)
+			}
+			if gptErr == nil || !errors.Is(err, ErrInvalidGPTHeader) {
+				gptErr = err
+			}
+		}
+	}
+
+	sectorSize := detectMBRSectorSize(r, size, entries)
+	mbr, err := readMBR(r, size, sectorSize, entries)
+	if err != nil {
+		return nil, err
+	}
+	if protective && len(mbr) == 1 {
+		// A protective MBR with no readable GUID partition table
+		return nil, fmt.Errorf("failed to read GUID partition table: %w", gptErr)
+	}
+
+	return &Layout{
+		Scheme:     MBR,
+		SectorSize: sectorSize,
+		Partitions: mbr,
+		MBR:        mbr,
+	}, nil
+}
+
+func gptLayout(r io.ReaderAt, size, sectorSize int64, table gptTable, entries []mbrEntry) (*Layout, error) {
+	layout := &Layout{
+		Scheme:     GPT,
+		SectorSize: sectorSize,
+		DiskGUID:   table.DiskGUID,
+		Partitions: table.Partitions,
+	}
+
+	// A hybrid MBR lists GPT partitions alongside the protective entry
+	mbr, err := readMBR(r, size, sectorSize, entries)
+	if err != nil {
+		return nil, err
+	}
+	layout.MBR = mbr
+	for _, p := range mbr {
+		if p.MBRType != mspart.ProtectiveMBR {
+			layout.Scheme = Hybrid
+		}
+	}
+
+	return layout, nil
+}
+
+// plausibleMBR returns true if entries looks like a partition table and
+// not the boot code of a volume boot record, which shares its signature.
+func plausibleMBR(entries []mbrEntry, size int64) bool {
+	found := false
+	for _, entry := range entries {
+		switch {
+		case entry.Type == mspart.Empty:
+			continue
+		case entry.Status != 0x00 && entry.Status != 0x80:
+			return false
+		case entry.Type == mspart.ProtectiveMBR:
+			// Protective entries may claim more sectors than the disk has
+		case int64(entry.Start)*sectorSizes[0] >= size:
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// detectMBRSectorSize returns the sector size that places the first
+// partition on a boot sector, or 512 if it can't be determined.
+func detectMBRSectorSize(r io.ReaderAt, size int64, entries []mbrEntry) int64 {
+	for _, entry := range entries {
+		if entry.Type == mspart.Empty || entry.Type.Extended() {
+			continue
+		}
+		for _, sectorSize := range sectorSizes {
+			offset := int64(entry.Start) * sectorSize
+			if offset+int64(entry.Sectors)*sectorSize > size {
+				continue
+			}
+			var sig [2]byte
+			if _, err := r.ReadAt(sig[:], offset+510); err != nil {
+				continue
+			}
+			if sig == bootSignature {
+				return sectorSize
+			}
+		}
+		break
+	}
+	return sectorSizes[0]
+}
@@ -0,0 +1,233 @@
+package disk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"testing"
+
+	"github.com/gentlemanautomaton/ntfs/mspart"
+)
+
+// testSectors is the number of sectors in each test disk.
+const testSectors = 256
+
+// testMBREntry is a master boot record partition entry.
+type testMBREntry struct {
+	status  byte
+	typ     mspart.MBR
+	start   uint32
+	sectors uint32
+}
+
+// putMBR writes a master or extended boot record holding entries to the
+// start of data.
+func putMBR(data []byte, entries ...testMBREntry) {
+	for i, e := range entries {
+		b := data[mbrTableOffset+i*mbrEntryLength:]
+		b[0] = e.status
+		b[4] = byte(e.typ)
+		binary.LittleEndian.PutUint32(b[8:12], e.start)
+		binary.LittleEndian.PutUint32(b[12:16], e.sectors)
+	}
+	copy(data[510:512], bootSignature[:])
+}
+
+// putGPTHeader writes a GUID partition table header to data, describing
+// an array of entryLength byte entries at entriesLBA.
+func putGPTHeader(data []byte, lba, backup, entriesLBA int64, entries []byte, entryLength uint32) {
+	h := data[:gptHeaderMinLength]
+	copy(h[0:8], gptSignature)
+	binary.LittleEndian.PutUint32(h[8:12], 0x10000)
+	binary.LittleEndian.PutUint32(h[12:16], gptHeaderMinLength)
+	binary.LittleEndian.PutUint64(h[24:32], uint64(lba))
+	binary.LittleEndian.PutUint64(h[32:40], uint64(backup))
+	binary.LittleEndian.PutUint64(h[40:48], 34)
+	binary.LittleEndian.PutUint64(h[48:56], testSectors-34)
+	copy(h[56:72], []byte{0x11, 0x11, 0x11, 0x11, 0x22, 0x22, 0x33, 0x33, 0x44, 0x44, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55})
+	binary.LittleEndian.PutUint64(h[72:80], uint64(entriesLBA))
+	binary.LittleEndian.PutUint32(h[80:84], uint32(len(entries))/entryLength)
+	binary.LittleEndian.PutUint32(h[84:88], entryLength)
+	binary.LittleEndian.PutUint32(h[88:92], crc32.ChecksumIEEE(entries))
+	binary.LittleEndian.PutUint32(h[16:20], 0)
+	binary.LittleEndian.PutUint32(h[16:20], crc32.ChecksumIEEE(h))
+}
+
+// testMBR returns a disk with 512-byte sectors holding a primary partition
+// and an extended partition with two logical partitions.
+func testMBR() []byte {
+	data := make([]byte, testSectors*512)
+	putMBR(data,
+		testMBREntry{0x80, mspart.IFS, 8, 16},
+		testMBREntry{0, mspart.ExtendedLBA, 32, 200},
+	)
+	copy(data[8*512+510:], bootSignature[:])
+	putMBR(data[32*512:],
+		testMBREntry{0, mspart.IFS, 8, 16},
+		testMBREntry{0, mspart.Extended, 40, 30},
+	)
+	putMBR(data[72*512:],
+		testMBREntry{0, mspart.FAT32LBA, 8, 16},
+	)
+	return data
+}
+
+// testGPT returns a disk with a GUID partition table holding a basic data
+// partition at sectors 40-79 and a reserved partition at sectors 80-87.
+// If hybrid is true the master boot record also lists the basic data
+// partition.
+func testGPT(sectorSize int64, hybrid bool) []byte {
+	data := make([]byte, testSectors*sectorSize)
+	entries := make([]byte, 128*gptEntryMinLength)
+	copy(entries[0:16], mspart.BasicData[:])
+	entries[16] = 1
+	binary.LittleEndian.PutUint64(entries[32:40], 40)
+	binary.LittleEndian.PutUint64(entries[40:48], 79)
+	copy(entries[56:], []byte{'D', 0, 'a', 0, 't', 0, 'a', 0})
+	copy(entries[128:144], mspart.MicrosoftReserved[:])
+	entries[144] = 2
+	binary.LittleEndian.PutUint64(entries[160:168], 80)
+	binary.LittleEndian.PutUint64(entries[168:176], 87)
+
+	mbr := []testMBREntry{{0, mspart.ProtectiveMBR, 1, testSectors - 1}}
+	if hybrid {
+		mbr = append(mbr, testMBREntry{0x80, mspart.IFS, 40, 40})
+	}
+	putMBR(data, mbr...)
+
+	last := int64(testSectors - 1)
+	backupEntries := last - (int64(len(entries))+sectorSize-1)/sectorSize
+	copy(data[2*sectorSize:], entries)
+	copy(data[backupEntries*sectorSize:], entries)
+	putGPTHeader(data[sectorSize:], 1, last, 2, entries, gptEntryMinLength)
+	putGPTHeader(data[last*sectorSize:], last, 1, backupEntries, entries, gptEntryMinLength)
+	return data
+}
+
+func TestRead(t *testing.T) {
+	type want struct {
+		number  int
+		offset  int64
+		length  int64
+		logical bool
+	}
+	tests := []struct {
+		name       string
+		data       []byte
+		scheme     Scheme
+		sectorSize int64
+		partitions []want
+		mbr        int
+	}{
+		{"MBR", testMBR(), MBR, 512, []want{
+			{1, 8 * 512, 16 * 512, false},
+			{2, 32 * 512, 200 * 512, false},
+			{5, 40 * 512, 16 * 512, true},
+			{6, 80 * 512, 16 * 512, true},
+		}, 4},
+		{"GPT512", testGPT(512, false), GPT, 512, []want{
+			{1, 40 * 512, 40 * 512, false},
+			{2, 80 * 512, 8 * 512, false},
+		}, 1},
+		{"GPT4096", testGPT(4096, false), GPT, 4096, []want{
+			{1, 40 * 4096, 40 * 4096, false},
+			{2, 80 * 4096, 8 * 4096, false},
+		}, 1},
+		{"Hybrid", testGPT(512, true), Hybrid, 512, []want{
+			{1, 40 * 512, 40 * 512, false},
+			{2, 80 * 512, 8 * 512, false},
+		}, 2},
+		{"DamagedPrimary", func() []byte {
+			data := testGPT(512, false)
+			data[512+30] ^= 0xFF
+			return data
+		}(), GPT, 512, []want{
+			{1, 40 * 512, 40 * 512, false},
+			{2, 80 * 512, 8 * 512, false},
+		}, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			layout, err := Read(bytes.NewReader(test.data), int64(len(test.data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if layout.Scheme != test.scheme || layout.SectorSize != test.sectorSize {
+				t.Errorf("got %s with %d-byte sectors, want %s with %d-byte sectors", layout.Scheme, layout.SectorSize, test.scheme, test.sectorSize)
+			}
+			if len(layout.MBR) != test.mbr {
+				t.Errorf("got %d MBR partitions, want %d", len(layout.MBR), test.mbr)
+			}
+			if len(layout.Partitions) != len(test.partitions) {
+				t.Fatalf("got %d partitions, want %d", len(layout.Partitions), len(test.partitions))
+			}
+			for i, w := range test.partitions {
+				p := layout.Partitions[i]
+				if p.Number != w.number || p.Offset != w.offset || p.Length != w.length || p.Logical != w.logical {
+					t.Errorf("partition %d: got %d [%d:%d] logical %t, want %d [%d:%d] logical %t", i,
+						p.Number, p.Offset, p.Offset+p.Length, p.Logical, w.number, w.offset, w.offset+w.length, w.logical)
+				}
+			}
+			if test.scheme != MBR {
+				if p := layout.Partitions[0]; p.GPTType != mspart.BasicData || p.Name != "Data" || !p.MayContainNTFS() {
+					t.Errorf("partition 1: got %s named %q", p.TypeName(), p.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{"Empty", make([]byte, testSectors*512), ErrNoPartitionTable},
+		{"DamagedBoth", func() []byte {
+			data := testGPT(512, false)
+			data[512+30] ^= 0xFF
+			data[(testSectors-1)*512+30] ^= 0xFF
+			return data
+		}(), ErrGPTChecksum},
+		{"ExtendedLoop", func() []byte {
+			data := testMBR()
+			putMBR(data[72*512:],
+				testMBREntry{0, mspart.FAT32LBA, 8, 16},
+				testMBREntry{0, mspart.Extended, 40, 30},
+			)
+			return data
+		}(), ErrExtendedPartitionLoop},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := Read(bytes.NewReader(test.data), int64(len(test.data))); !errors.Is(err, test.err) {
+				t.Errorf("got %v, want %v", err, test.err)
+			}
+		})
+	}
+}
+
+func TestParseGPTHeaderEntryLength(t *testing.T) {
+	tests := []struct {
+		name   string
+		length uint32
+		err    error
+	}{
+		{"Min", gptEntryMinLength, nil},
+		{"Max", gptEntryMaxLength, nil},
+		{"Short", gptEntryMinLength - 8, ErrInvalidGPTHeader},
+		{"Unaligned", gptEntryMinLength + 4, ErrInvalidGPTHeader},
+		{"Long", gptEntryMaxLength + 8, ErrInvalidGPTHeader},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := make([]byte, 512)
+			putGPTHeader(data, 1, testSectors-1, 2, make([]byte, test.length), test.length)
+			if _, err := parseGPTHeader(data, 1); !errors.Is(err, test.err) {
+				t.Errorf("got %v, want %v", err, test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package disk
+
+import "errors"
+
+var (
+	// ErrNoPartitionTable is returned when a disk has neither a valid master
+	// boot record nor a valid GUID partition table.
+	ErrNoPartitionTable = errors.New("no partition table found")
+
+	// ErrInvalidGPTHeader is returned when a GUID partition table header
+	// has an invalid signature, size or entry layout.
+	ErrInvalidGPTHeader = errors.New("invalid GUID partition table header")
+
+	// ErrGPTChecksum is returned when the CRC32 checksum of a GUID partition
+	// table header or its partition entry array does not match its contents.
+	ErrGPTChecksum = errors.New("GUID partition table checksum mismatch")
+
+	// ErrExtendedPartitionLoop is returned when the chain of extended boot
+	// records in an extended partition refers back to itself.
+	ErrExtendedPartitionLoop = errors.New("extended partition chain contains a loop")
+
+	// ErrTooManyPartitions is returned when an extended partition chain or
+	// partition entry array exceeds the number of partitions supported.
+	ErrTooManyPartitions = errors.New("too many partitions")
+)
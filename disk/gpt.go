@@ -0,0 +1,158 @@
+package disk
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"unicode/utf16"
+)
+
+// https://uefi.org/specs/UEFI/2.10/05_GUID_Partition_Table_Format.html
+
+const (
+	gptSignature       = "EFI PART"
+	gptHeaderMinLength = 92
+	gptEntryMinLength  = 128
+	gptEntryMaxLength  = 4096
+	gptMaxEntries      = 1024
+)
+
+// gptTable holds the contents of a GUID partition table.
+type gptTable struct {
+	DiskGUID   GUID
+	Partitions []Partition
+}
+
+// gptHeader is a GUID partition table header.
+type gptHeader struct {
+	CurrentLBA     int64
+	BackupLBA      int64
+	DiskGUID       GUID
+	EntriesLBA     int64
+	NumEntries     uint32
+	EntryLength    uint32
+	EntriesCRC32   uint32
+	FirstUsableLBA int64
+	LastUsableLBA  int64
+}
+
+// readGPT reads the GUID partition table of a disk with the given sector
+// size. The backup table at the end of the disk is used if the primary
+// table is damaged.
+func readGPT(r io.ReaderAt, size, sectorSize int64) (gptTable, error) {
+	table, err := readGPTAt(r, size, sectorSize, 1)
+	if err == nil {
+		return table, nil
+	}
+	if last := size/sectorSize - 1; last > 1 {
+		if backup, backupErr := readGPTAt(r, size, sectorSize, last); backupErr == nil {
+			return backup, nil
+		}
+	}
+	return gptTable{}, err
+}
+
+func readGPTAt(r io.ReaderAt, size, sectorSize, lba int64) (gptTable, error) {
+	data := make([]byte, sectorSize)
+	if _, err := r.ReadAt(data, lba*sectorSize); err != nil {
+		return gptTable{}, fmt.Errorf("%w: %v", ErrInvalidGPTHeader, err)
+	}
+	header, err := parseGPTHeader(data, lba)
+	if err != nil {
+		return gptTable{}, err
+	}
+
+	length := int64(header.NumEntries) * int64(header.EntryLength)
+	offset := header.EntriesLBA * sectorSize
+	if offset+length > size {
+		return gptTable{}, fmt.Errorf("%w: partition entries lie beyond the end of the disk", ErrInvalidGPTHeader)
+	}
+	entries := make([]byte, length)
+	if _, err := r.ReadAt(entries, offset); err != nil {
+		return gptTable{}, fmt.Errorf("failed to read GUID partition entries: %w", err)
+	}
+	if crc32.ChecksumIEEE(entries) != header.EntriesCRC32 {
+		return gptTable{}, fmt.Errorf("%w: partition entries", ErrGPTChecksum)
+	}
+
+	table := gptTable{DiskGUID: header.DiskGUID}
+	for i := 0; i < int(header.NumEntries); i++ {
+		b := entries[i*int(header.EntryLength):]
+		var p Partition
+		copy(p.GPTType[:], b[0:16])
+		if p.GPTType.IsZero() {
+			continue
+		}
+		copy(p.GUID[:], b[16:32])
+		first := int64(binary.LittleEndian.Uint64(b[32:40]))
+		last := int64(binary.LittleEndian.Uint64(b[40:48]))
+		if first < 0 || last < first || (last+1)*sectorSize > size {
+			return gptTable{}, fmt.Errorf("%w: partition %d has an invalid range [%d:%d]", ErrInvalidGPTHeader, i+1, first, last)
+		}
+		p.Number = i + 1
+		p.Scheme = GPT
+		p.Offset = first * sectorSize
+		p.Length = (last - first + 1) * sectorSize
+		p.Attributes = binary.LittleEndian.Uint64(b[48:56])
+		p.Name = decodeName(b[56:128])
+		table.Partitions = append(table.Partitions, p)
+	}
+	return table, nil
+}
+
+// parseGPTHeader parses the GUID partition table header in data, which is
+// expected to be found at the given logical block address.
+func parseGPTHeader(data []byte, lba int64) (gptHeader, error) {
+	if len(data) < gptHeaderMinLength || string(data[0:8]) != gptSignature {
+		return gptHeader{}, ErrInvalidGPTHeader
+	}
+	length := binary.LittleEndian.Uint32(data[12:16])
+	if length < gptHeaderMinLength || int(length) > len(data) {
+		return gptHeader{}, fmt.Errorf("%w: header length %d", ErrInvalidGPTHeader, length)
+	}
+
+	// The checksum is calculated with its own field zeroed
+	sum := binary.LittleEndian.Uint32(data[16:20])
+	buf := append([]byte(nil), data[:length]...)
+	copy(buf[16:20], []byte{0, 0, 0, 0})
+	if crc32.ChecksumIEEE(buf) != sum {
+		return gptHeader{}, fmt.Errorf("%w: header", ErrGPTChecksum)
+	}
+
+	var h gptHeader
+	h.CurrentLBA = int64(binary.LittleEndian.Uint64(data[24:32]))
+	h.BackupLBA = int64(binary.LittleEndian.Uint64(data[32:40]))
+	h.FirstUsableLBA = int64(binary.LittleEndian.Uint64(data[40:48]))
+	h.LastUsableLBA = int64(binary.LittleEndian.Uint64(data[48:56]))
+	copy(h.DiskGUID[:], data[56:72])
+	h.EntriesLBA = int64(binary.LittleEndian.Uint64(data[72:80]))
+	h.NumEntries = binary.LittleEndian.Uint32(data[80:84])
+	h.EntryLength = binary.LittleEndian.Uint32(data[84:88])
+	h.EntriesCRC32 = binary.LittleEndian.Uint32(data[88:92])
+
+	switch {
+	case h.CurrentLBA != lba:
+		return gptHeader{}, fmt.Errorf("%w: header claims to be at LBA %d instead of %d", ErrInvalidGPTHeader, h.CurrentLBA, lba)
+	case h.EntryLength < gptEntryMinLength || h.EntryLength > gptEntryMaxLength || h.EntryLength%8 != 0:
+		return gptHeader{}, fmt.Errorf("%w: partition entry length %d", ErrInvalidGPTHeader, h.EntryLength)
+	case h.NumEntries > gptMaxEntries:
+		return gptHeader{}, ErrTooManyPartitions
+	case h.EntriesLBA < 0:
+		return gptHeader{}, fmt.Errorf("%w: partition entries at LBA %d", ErrInvalidGPTHeader, h.EntriesLBA)
+	}
+	return h, nil
+}
+
+// decodeName decodes a null-terminated UTF-16LE partition name.
+func decodeName(b []byte) string {
+	u := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		c := binary.LittleEndian.Uint16(b[i:])
+		if c == 0 {
+			break
+		}
+		u = append(u, c)
+	}
+	return string(utf16.Decode(u))
+}
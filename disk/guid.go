@@ -0,0 +1,22 @@
+package disk
+
+import "fmt"
+
+// GUID is a globally unique identifier in its on-disk, mixed-endian byte
+// order. It can be compared directly with the partition types in mspart.
+type GUID [16]byte
+
+// IsZero returns true if g is all zeroes.
+func (g GUID) IsZero() bool {
+	return g == GUID{}
+}
+
+// String returns the canonical string representation of g.
+func (g GUID) String() string {
+	return fmt.Sprintf("%02X%02X%02X%02X-%02X%02X-%02X%02X-%02X%02X-%02X%02X%02X%02X%02X%02X",
+		g[3], g[2], g[1], g[0],
+		g[5], g[4],
+		g[7], g[6],
+		g[8], g[9],
+		g[10], g[11], g[12], g[13], g[14], g[15])
+}
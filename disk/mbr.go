@@ -0,0 +1,129 @@
+package disk
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/gentlemanautomaton/ntfs/mspart"
+)
+
+// https://en.wikipedia.org/wiki/Master_boot_record
+// https://en.wikipedia.org/wiki/Extended_boot_record
+
+const (
+	mbrLength         = 512
+	mbrTableOffset    = 446
+	mbrEntryLength    = 16
+	mbrEntries        = 4
+	maxLogicalEntries = 128
+)
+
+var bootSignature = [2]byte{0x55, 0xAA}
+
+// mbrEntry is a partition entry in a master boot record or extended boot
+// record. Its start is relative to the record that contains it.
+type mbrEntry struct {
+	Status  byte
+	Type    mspart.MBR
+	Start   uint32
+	Sectors uint32
+}
+
+// parseMBR parses the partition entries of a master or extended boot
+// record. It returns false if the boot signature is missing.
+func parseMBR(data []byte) (entries []mbrEntry, ok bool) {
+	if len(data) < mbrLength || [2]byte{data[510], data[511]} != bootSignature {
+		return nil, false
+	}
+	entries = make([]mbrEntry, mbrEntries)
+	for i := range entries {
+		b := data[mbrTableOffset+i*mbrEntryLength:]
+		entries[i] = mbrEntry{
+			Status:  b[0],
+			Type:    mspart.MBR(b[4]),
+			Start:   binary.LittleEndian.Uint32(b[8:12]),
+			Sectors: binary.LittleEndian.Uint32(b[12:16]),
+		}
+	}
+	return entries, true
+}
+
+// readMBR returns the primary partitions in entries followed by the logical
+// partitions of any extended partitions.
+func readMBR(r io.ReaderAt, size, sectorSize int64, entries []mbrEntry) ([]Partition, error) {
+	var partitions, logical []Partition
+	for i, entry := range entries {
+		if entry.Type == mspart.Empty || entry.Sectors == 0 {
+			continue
+		}
+		partitions = append(partitions, Partition{
+			Number:   i + 1,
+			Scheme:   MBR,
+			Offset:   int64(entry.Start) * sectorSize,
+			Length:   int64(entry.Sectors) * sectorSize,
+			MBRType:  entry.Type,
+			Bootable: entry.Status&0x80 != 0,
+		})
+		if entry.Type.Extended() {
+			more, err := readExtended(r, size, sectorSize, int64(entry.Start), mbrEntries+len(logical)+1)
+			if err != nil {
+				return nil, err
+			}
+			logical = append(logical, more...)
+		}
+	}
+	return append(partitions, logical...), nil
+}
+
+// readExtended follows the chain of extended boot records in the extended
+// partition starting at base, which is given in sectors.
+func readExtended(r io.ReaderAt, size, sectorSize, base int64, number int) ([]Partition, error) {
+	var (
+		partitions []Partition
+		seen       = make(map[int64]bool)
+		ebr        = base
+		data       = make([]byte, mbrLength)
+	)
+	for {
+		if seen[ebr] {
+			return nil, ErrExtendedPartitionLoop
+		}
+		seen[ebr] = true
+		if len(partitions) >= maxLogicalEntries {
+			return nil, ErrTooManyPartitions
+		}
+
+		if ebr*sectorSize+mbrLength > size {
+			return nil, fmt.Errorf("extended boot record at sector %d lies beyond the end of the disk", ebr)
+		}
+		if _, err := r.ReadAt(data, ebr*sectorSize); err != nil {
+			return nil, fmt.Errorf("failed to read extended boot record at sector %d: %w", ebr, err)
+		}
+		entries, ok := parseMBR(data)
+		if !ok {
+			// A blank record terminates the chain
+			return partitions, nil
+		}
+
+		// The first entry describes a logical partition relative to this
+		// record, the second links to the next record relative to base.
+		if entry := entries[0]; entry.Type != mspart.Empty && entry.Sectors > 0 {
+			partitions = append(partitions, Partition{
+				Number:   number,
+				Scheme:   MBR,
+				Offset:   (ebr + int64(entry.Start)) * sectorSize,
+				Length:   int64(entry.Sectors) * sectorSize,
+				MBRType:  entry.Type,
+				Bootable: entry.Status&0x80 != 0,
+				Logical:  true,
+			})
+			number++
+		}
+		next := entries[1]
+		if !next.Type.Extended() || next.Start == 0 {
+			return partitions, nil
+		}
+		ebr = base + int64(next.Start)
+	}
+}
@@ -0,0 +1,138 @@
+package ntfs
+
+import (
+	"fmt"
+
+	"github.com/gentlemanautomaton/ntfs/attrflag"
+	"github.com/gentlemanautomaton/ntfs/attrtype"
+	"github.com/gentlemanautomaton/ntfs/datarun"
+)
+
+// Extent describes a contiguous region of a stream and the location of its
+// data on the volume.
+type Extent struct {
+	VCN            VCN   // The first virtual cluster number of the extent
+	LCN            int64 // The first logical cluster number of the extent, or -1 if sparse
+	Clusters       int64 // The length of the extent in clusters
+	Offset         int64 // The logical offset of the extent within the stream in bytes
+	PhysicalOffset int64 // The offset of the extent within the volume in bytes, or -1 if sparse
+	Length         int64 // The length of the extent in bytes
+	Sparse         bool  // The extent isn't backed by any clusters
+	Compressed     bool  // The extent is part of a compressed compression unit
+}
+
+// String returns a description of the extent.
+func (e Extent) String() string {
+	flags := ""
+	if e.Sparse {
+		flags += " sparse"
+	}
+	if e.Compressed {
+		flags += " compressed"
+	}
+	if e.Sparse {
+		return fmt.Sprintf("offset %d length %d: VCN %d+%d%s", e.Offset, e.Length, e.VCN, e.Clusters, flags)
+	}
+	return fmt.Sprintf("offset %d length %d: VCN %d+%d LCN %d physical %d%s", e.Offset, e.Length, e.VCN, e.Clusters, e.LCN, e.PhysicalOffset, flags)
+}
+
+// Extents returns the extents of the file's unnamed $DATA stream. It
+// reports the same information as FSCTL_GET_RETRIEVAL_POINTERS.
+//
+// The file must have been retrieved from a Reader. Resident streams have
+// no extents.
+func (file *File) Extents() ([]Extent, error) {
+	return file.StreamExtents("")
+}
+
+// StreamExtents returns the extents of the file's $DATA stream with the
+// given name.
+func (file *File) StreamExtents(name string) ([]Extent, error) {
+	if file.reader == nil {
+		return nil, ErrNoReader
+	}
+	return file.reader.Extents(file, attrtype.Data, name)
+}
+
+// Extents returns the extents of the attribute of file with the given type
+// code and name. All of the attribute's segments are included.
+//
+// The clusters of compressed attributes are reported one compression unit
+// at a time, so that each unit that is stored in compressed form can be
+// flagged.
+func (r *Reader) Extents(file *File, code attrtype.Code, name string) ([]Extent, error) {
+	segments, err := r.Segments(file, code, name)
+	if err != nil {
+		return nil, err
+	}
+	first := &segments[0]
+	if first.Header.Resident() {
+		return nil, nil
+	}
+	if r.Standalone() {
+		return nil, ErrNoVolume
+	}
+	runlist, err := segmentRunlist(segments)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterSize := int64(r.boot.ClusterSize())
+	unit := uint64(0)
+	if first.Header.Flags&attrflag.CompressionMask != 0 && first.Nonresident.CompressionUnit > 0 {
+		unit = 1 << first.Nonresident.CompressionUnit
+	}
+
+	var extents []Extent
+	add := func(e datarun.Extent, compressed bool) {
+		extent := Extent{
+			VCN:            VCN(e.VCN),
+			LCN:            e.LCN,
+			Clusters:       int64(e.Length),
+			Offset:         int64(e.VCN) * clusterSize,
+			PhysicalOffset: e.LCN * clusterSize,
+			Length:         int64(e.Length) * clusterSize,
+			Sparse:         e.Sparse,
+			Compressed:     compressed,
+		}
+		if e.Sparse {
+			extent.LCN, extent.PhysicalOffset = -1, -1
+		}
+
+		// Coalesce extents that continue the previous one
+		if n := len(extents); n > 0 {
+			prev := &extents[n-1]
+			if prev.Sparse == extent.Sparse && prev.Compressed == extent.Compressed &&
+				prev.VCN+VCN(prev.Clusters) == extent.VCN &&
+				(extent.Sparse || prev.LCN+prev.Clusters == extent.LCN) {
+				prev.Clusters += extent.Clusters
+				prev.Length += extent.Length
+				return
+			}
+		}
+		extents = append(extents, extent)
+	}
+
+	if len(runlist) == 0 {
+		return nil, nil
+	}
+	if unit == 0 {
+		for _, e := range runlist {
+			add(e, false)
+		}
+		return extents, nil
+	}
+
+	// A compression unit is stored in compressed form when it is only
+	// partially backed by clusters.
+	end := runlist[len(runlist)-1].End()
+	for vcn := runlist[0].VCN - runlist[0].VCN%unit; vcn < end; vcn += unit {
+		units := runlist.Range(vcn, unit)
+		allocated := units.Allocated()
+		compressed := allocated > 0 && allocated < unit
+		for _, e := range units {
+			add(e, compressed && !e.Sparse)
+		}
+	}
+	return extents, nil
+}
@@ -1,7 +1,54 @@
 package ntfs
 
+import "github.com/gentlemanautomaton/ntfs/attrtype"
+
 // File represents a file within an NTFS master file table.
 type File struct {
+	ID         int64 // The file record number
 	Header     FileRecordSegmentHeader
 	Attributes []Attribute
+
+	reader *Reader // The reader the file was retrieved from, if any
+}
+
+// Attribute returns the first attribute of file with the given type code.
+// If the file doesn't have an attribute of that type ok will be false.
+func (file *File) Attribute(code attrtype.Code) (attr *Attribute, ok bool) {
+	for i := range file.Attributes {
+		if file.Attributes[i].Header.TypeCode == code {
+			return &file.Attributes[i], true
+		}
+	}
+	return nil, false
+}
+
+// instance returns the attribute of file with the given type code and
+// instance number.
+func (file *File) instance(code attrtype.Code, instance uint16) (attr *Attribute, ok bool) {
+	for i := range file.Attributes {
+		if file.Attributes[i].Header.TypeCode == code && file.Attributes[i].Header.Instance == instance {
+			return &file.Attributes[i], true
+		}
+	}
+	return nil, false
+}
+
+// NamedAttribute returns the attribute of file with the given type code and
+// name. If the file doesn't have a matching attribute ok will be false.
+func (file *File) NamedAttribute(code attrtype.Code, name string) (attr *Attribute, ok bool) {
+	for i := range file.Attributes {
+		if file.Attributes[i].Header.TypeCode == code && file.Attributes[i].Name == name {
+			return &file.Attributes[i], true
+		}
+	}
+	return nil, false
+}
+
+// Reference returns a reference to the base file record of file. For
+// extension records this is the base record they belong to.
+func (file *File) Reference() FileReference {
+	if !file.Header.BaseFileRecordSegment.IsZero() {
+		return file.Header.BaseFileRecordSegment
+	}
+	return NewSegmentReference(file.ID, file.Header.SequenceNumber)
 }
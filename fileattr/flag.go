@@ -0,0 +1,129 @@
+package fileattr
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// https://docs.microsoft.com/windows/win32/fileio/file-attribute-constants
+
+// Flag is a file attribute flag, as stored in standard information,
+// file name and USN journal records.
+type Flag uint32
+
+// File attribute flags.
+const (
+	ReadOnly             Flag = 0x00000001 // FILE_ATTRIBUTE_READONLY
+	Hidden               Flag = 0x00000002 // FILE_ATTRIBUTE_HIDDEN
+	System               Flag = 0x00000004 // FILE_ATTRIBUTE_SYSTEM
+	Directory            Flag = 0x00000010 // FILE_ATTRIBUTE_DIRECTORY
+	Archive              Flag = 0x00000020 // FILE_ATTRIBUTE_ARCHIVE
+	Device               Flag = 0x00000040 // FILE_ATTRIBUTE_DEVICE
+	Normal               Flag = 0x00000080 // FILE_ATTRIBUTE_NORMAL
+	Temporary            Flag = 0x00000100 // FILE_ATTRIBUTE_TEMPORARY
+	SparseFile           Flag = 0x00000200 // FILE_ATTRIBUTE_SPARSE_FILE
+	ReparsePoint         Flag = 0x00000400 // FILE_ATTRIBUTE_REPARSE_POINT
+	Compressed           Flag = 0x00000800 // FILE_ATTRIBUTE_COMPRESSED
+	Offline              Flag = 0x00001000 // FILE_ATTRIBUTE_OFFLINE
+	NotContentIndexed    Flag = 0x00002000 // FILE_ATTRIBUTE_NOT_CONTENT_INDEXED
+	Encrypted            Flag = 0x00004000 // FILE_ATTRIBUTE_ENCRYPTED
+	IntegrityStream      Flag = 0x00008000 // FILE_ATTRIBUTE_INTEGRITY_STREAM
+	Virtual              Flag = 0x00010000 // FILE_ATTRIBUTE_VIRTUAL
+	NoScrubData          Flag = 0x00020000 // FILE_ATTRIBUTE_NO_SCRUB_DATA
+	FileNameIndexPresent Flag = 0x10000000 // DUP_FILE_NAME_INDEX_PRESENT
+	ViewIndexPresent     Flag = 0x20000000 // DUP_VIEW_INDEX_PRESENT
+	KnownMask            Flag = ReadOnly | Hidden | System | Directory | Archive | Device | Normal | Temporary | SparseFile | ReparsePoint | Compressed | Offline | NotContentIndexed | Encrypted | IntegrityStream | Virtual | NoScrubData | FileNameIndexPresent | ViewIndexPresent
+	UnknownMask          Flag = ^KnownMask
+)
+
+// Unmarshal unmarshals the little-endian binary representation
+// of file attribute flags.
+//
+// The provided data must be at least 4 bytes long, or unmarshal will
+// panic.
+func Unmarshal(data []byte) Flag {
+	return Flag(binary.LittleEndian.Uint32(data[0:4]))
+}
+
+// String returns a description of the file attribute flags.
+func (f Flag) String() string {
+	var flags []string
+
+	// Report known flags
+	if f&ReadOnly != 0 {
+		flags = append(flags, "ReadOnly")
+	}
+	if f&Hidden != 0 {
+		flags = append(flags, "Hidden")
+	}
+	if f&System != 0 {
+		flags = append(flags, "System")
+	}
+	if f&Directory != 0 {
+		flags = append(flags, "Directory")
+	}
+	if f&Archive != 0 {
+		flags = append(flags, "Archive")
+	}
+	if f&Device != 0 {
+		flags = append(flags, "Device")
+	}
+	if f&Normal != 0 {
+		flags = append(flags, "Normal")
+	}
+	if f&Temporary != 0 {
+		flags = append(flags, "Temporary")
+	}
+	if f&SparseFile != 0 {
+		flags = append(flags, "SparseFile")
+	}
+	if f&ReparsePoint != 0 {
+		flags = append(flags, "ReparsePoint")
+	}
+	if f&Compressed != 0 {
+		flags = append(flags, "Compressed")
+	}
+	if f&Offline != 0 {
+		flags = append(flags, "Offline")
+	}
+	if f&NotContentIndexed != 0 {
+		flags = append(flags, "NotContentIndexed")
+	}
+	if f&Encrypted != 0 {
+		flags = append(flags, "Encrypted")
+	}
+	if f&IntegrityStream != 0 {
+		flags = append(flags, "IntegrityStream")
+	}
+	if f&Virtual != 0 {
+		flags = append(flags, "Virtual")
+	}
+	if f&NoScrubData != 0 {
+		flags = append(flags, "NoScrubData")
+	}
+	if f&FileNameIndexPresent != 0 {
+		flags = append(flags, "FileNameIndexPresent")
+	}
+	if f&ViewIndexPresent != 0 {
+		flags = append(flags, "ViewIndexPresent")
+	}
+
+	// Report unknown flags
+	if f&UnknownMask != 0 {
+		for i := uint(0); i < 32; i++ {
+			q := Flag(1) << i
+			// Find flags that are present
+			if q&f == 0 {
+				continue
+			}
+			// Skip flags that we've already identified
+			if q&UnknownMask == 0 {
+				continue
+			}
+			flags = append(flags, fmt.Sprintf("%#08x", uint32(q)))
+		}
+	}
+
+	return strings.Join(flags, ",")
+}
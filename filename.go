@@ -1,6 +1,10 @@
 package ntfs
 
 import (
+	"encoding/binary"
+	"time"
+
+	"github.com/gentlemanautomaton/ntfs/fileattr"
 	"github.com/gentlemanautomaton/ntfs/filenameflag"
 )
 
@@ -9,13 +13,25 @@ const FileNameHeaderLength = 66
 
 // FileName stores file name attribute information.
 //
+// The timestamps and sizes are only updated by the file system when the
+// file name changes, so they frequently lag behind the values in
+// StandardInformation and the $DATA attribute.
+//
 // https://msdn.microsoft.com/library/bb470123
+// https://flatcap.org/linux-ntfs/ntfs/attributes/file_name.html
 type FileName struct {
-	ParentDirectory FileReference
-	reserved        [56]byte // Reserved
-	FileNameLength  uint8
-	Flags           filenameflag.Flag
-	Value           string
+	ParentDirectory    FileReference
+	FileCreation       time.Time
+	FileModification   time.Time
+	MFTModification    time.Time
+	FileRead           time.Time
+	AllocatedSize      uint64
+	RealSize           uint64
+	DOSFilePermissions fileattr.Flag
+	Reparse            uint32 // Reparse tag or packed extended attribute size
+	FileNameLength     uint8
+	Flags              filenameflag.Flag
+	Value              string
 }
 
 // UnmarshalBinary unmarshals the little-endian binary representation
@@ -27,7 +43,14 @@ func (entry *FileName) UnmarshalBinary(data []byte) error {
 	if err := entry.ParentDirectory.UnmarshalBinary(data[0:8]); err != nil {
 		return err
 	}
-	copy(entry.reserved[:], data[8:64])
+	entry.FileCreation = unmarshalFileTime(data[8:16])
+	entry.FileModification = unmarshalFileTime(data[16:24])
+	entry.MFTModification = unmarshalFileTime(data[24:32])
+	entry.FileRead = unmarshalFileTime(data[32:40])
+	entry.AllocatedSize = binary.LittleEndian.Uint64(data[40:48])
+	entry.RealSize = binary.LittleEndian.Uint64(data[48:56])
+	entry.DOSFilePermissions = fileattr.Unmarshal(data[56:60])
+	entry.Reparse = binary.LittleEndian.Uint32(data[60:64])
 	entry.FileNameLength = uint8(data[64])
 	entry.Flags = filenameflag.Flag(data[65])
 	start := 66
@@ -40,3 +63,51 @@ func (entry *FileName) UnmarshalBinary(data []byte) error {
 	entry.Value, err = utf16ToString(data[start:end])
 	return err
 }
+
+// MarshalBinary returns the little-endian binary representation of entry.
+//
+// The length of the file name is derived from Value. If it doesn't match
+// FileNameLength ErrFileNameLengthMismatch is returned.
+func (entry *FileName) MarshalBinary() ([]byte, error) {
+	name := stringToUTF16(entry.Value)
+	if len(name)/2 != int(entry.FileNameLength) {
+		return nil, ErrFileNameLengthMismatch
+	}
+	data := make([]byte, FileNameHeaderLength+len(name))
+	entry.ParentDirectory.marshal(data[0:8])
+	marshalFileTime(data[8:16], entry.FileCreation)
+	marshalFileTime(data[16:24], entry.FileModification)
+	marshalFileTime(data[24:32], entry.MFTModification)
+	marshalFileTime(data[32:40], entry.FileRead)
+	binary.LittleEndian.PutUint64(data[40:48], entry.AllocatedSize)
+	binary.LittleEndian.PutUint64(data[48:56], entry.RealSize)
+	binary.LittleEndian.PutUint32(data[56:60], uint32(entry.DOSFilePermissions))
+	binary.LittleEndian.PutUint32(data[60:64], entry.Reparse)
+	data[64] = entry.FileNameLength
+	data[65] = uint8(entry.Flags)
+	copy(data[66:], name)
+	return data, nil
+}
+
+// ReparsePoint returns true if the file is a reparse point.
+func (entry *FileName) ReparsePoint() bool {
+	return entry.DOSFilePermissions&fileattr.ReparsePoint != 0
+}
+
+// ReparseTag returns the reparse tag of the file. It returns zero if the
+// file is not a reparse point.
+func (entry *FileName) ReparseTag() uint32 {
+	if !entry.ReparsePoint() {
+		return 0
+	}
+	return entry.Reparse
+}
+
+// ExtendedAttributeSize returns the packed size of the file's extended
+// attributes in bytes. It returns zero if the file is a reparse point.
+func (entry *FileName) ExtendedAttributeSize() uint16 {
+	if entry.ReparsePoint() {
+		return 0
+	}
+	return uint16(entry.Reparse)
+}
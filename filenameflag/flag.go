@@ -16,6 +16,34 @@ const (
 	UnknownMask Flag = ^KnownMask
 )
 
+// File name namespaces. A file name's flags identify the namespace it
+// belongs to.
+const (
+	POSIX       Flag = 0x00       // Case-sensitive name with any character except NUL and slash
+	Win32       Flag = NTFS       // Long name that is case-insensitive
+	Win32AndDOS Flag = NTFS | DOS // Long name that is also a valid 8.3 name
+)
+
+// Namespace returns the name of the namespace identified by f.
+func (f Flag) Namespace() string {
+	switch f & KnownMask {
+	case POSIX:
+		return "POSIX"
+	case Win32:
+		return "Win32"
+	case DOS:
+		return "DOS"
+	default:
+		return "Win32&DOS"
+	}
+}
+
+// Long returns true if f identifies a name that is not only a DOS 8.3
+// alias.
+func (f Flag) Long() bool {
+	return f&KnownMask != DOS
+}
+
 // String returns a description of the file name flags.
 func (f Flag) String() string {
 	var flags []string
@@ -3,32 +3,61 @@ package ntfs
 import (
 	"encoding/binary"
 	"io"
+
+	"github.com/gentlemanautomaton/ntfs/filerecordflag"
 )
 
 // FileRecordSegmentHeaderLength is the length of a file record segment
 // header in bytes.
 const FileRecordSegmentHeaderLength = 42
 
+// FileRecordSegmentHeaderExtendedLength is the length of a file record
+// segment header in bytes on NTFS 3.1 and later volumes, which store the
+// record number in the header.
+const FileRecordSegmentHeaderExtendedLength = 48
+
 // FileRecordSegmentHeader stores file record segment information. It is
 // present at the start of each file record, and is immediately followed
 // by the update sequence array.
 //
 // https://msdn.microsoft.com/library/bb470124
+// https://flatcap.org/linux-ntfs/ntfs/concepts/file_record.html
 type FileRecordSegmentHeader struct {
 	MultiSectorHeader
-	logFileSequenceNumber uint64 // reserved
+	LogFileSequenceNumber uint64
 	SequenceNumber        uint16
-	hardLinkCount         uint16 // reserved
+	HardLinkCount         uint16
 	FirstAttributeOffset  uint16 // relative to the start of the header
-	Flags                 uint16
-	actualSize            uint32 // reserved
-	allocatedSize         uint32 // reserved
+	Flags                 filerecordflag.Flag
+	ActualSize            uint32 // Bytes in use by the record
+	AllocatedSize         uint32 // Bytes allocated for the record
 	BaseFileRecordSegment FileReference
-	reserved4             uint16 // reserved
+	NextAttributeID       uint16
+	reserved              uint16 // NTFS 3.1 alignment
+	RecordNumber          uint32 // NTFS 3.1 only
+}
+
+// InUse returns true if the file record segment is in use.
+func (header *FileRecordSegmentHeader) InUse() bool {
+	return header.Flags&filerecordflag.InUse != 0
+}
+
+// Directory returns true if the file record segment has a file name index,
+// which is the case for directories.
+func (header *FileRecordSegmentHeader) Directory() bool {
+	return header.Flags&filerecordflag.Directory != 0
+}
+
+// Extended returns true if the header includes the NTFS 3.1 record number.
+// This is the case when the update sequence array follows it.
+func (header *FileRecordSegmentHeader) Extended() bool {
+	return header.UpdateSequenceArrayOffset >= FileRecordSegmentHeaderExtendedLength
 }
 
 // ReadFrom reads 42 bytes of file record segment header data
 // from r into header.
+//
+// The NTFS 3.1 record number is not read.
 func (header *FileRecordSegmentHeader) ReadFrom(r io.Reader) (n int64, err error) {
 	var buf [FileRecordSegmentHeaderLength]byte
 	n32, err := r.Read(buf[:])
@@ -45,7 +74,8 @@ func (header *FileRecordSegmentHeader) ReadFrom(r io.Reader) (n int64, err error
 // UnmarshalBinary unmarshals the little-endian binary representation
 // of a file record segment header into header.
 //
-// The provided data must be at least 42 bytes long.
+// The provided data must be at least 42 bytes long. The record number
+// is read if the header is extended and data is at least 48 bytes long.
 func (header *FileRecordSegmentHeader) UnmarshalBinary(data []byte) error {
 	if len(data) < FileRecordSegmentHeaderLength {
 		return ErrTruncatedData
@@ -53,16 +83,47 @@ func (header *FileRecordSegmentHeader) UnmarshalBinary(data []byte) error {
 	if err := header.MultiSectorHeader.UnmarshalBinary(data[0:8]); err != nil {
 		return err
 	}
-	header.logFileSequenceNumber = binary.LittleEndian.Uint64(data[8:16])
+	header.LogFileSequenceNumber = binary.LittleEndian.Uint64(data[8:16])
 	header.SequenceNumber = binary.LittleEndian.Uint16(data[16:18])
-	header.hardLinkCount = binary.LittleEndian.Uint16(data[18:20])
+	header.HardLinkCount = binary.LittleEndian.Uint16(data[18:20])
 	header.FirstAttributeOffset = binary.LittleEndian.Uint16(data[20:22])
-	header.Flags = binary.LittleEndian.Uint16(data[22:24])
-	header.actualSize = binary.LittleEndian.Uint32(data[24:28])
-	header.allocatedSize = binary.LittleEndian.Uint32(data[28:32])
+	header.Flags = filerecordflag.Unmarshal(data[22:24])
+	header.ActualSize = binary.LittleEndian.Uint32(data[24:28])
+	header.AllocatedSize = binary.LittleEndian.Uint32(data[28:32])
 	if err := header.BaseFileRecordSegment.UnmarshalBinary(data[32:40]); err != nil {
 		return err
 	}
-	header.reserved4 = binary.LittleEndian.Uint16(data[40:42])
+	header.NextAttributeID = binary.LittleEndian.Uint16(data[40:42])
+	header.reserved, header.RecordNumber = 0, 0
+	if header.Extended() && len(data) >= FileRecordSegmentHeaderExtendedLength {
+		header.reserved = binary.LittleEndian.Uint16(data[42:44])
+		header.RecordNumber = binary.LittleEndian.Uint32(data[44:48])
+	}
 	return nil
 }
+
+// MarshalBinary returns the little-endian binary representation of
+// header. The returned data is 48 bytes long if the header is extended,
+// and 42 bytes long otherwise.
+func (header *FileRecordSegmentHeader) MarshalBinary() ([]byte, error) {
+	length := FileRecordSegmentHeaderLength
+	if header.Extended() {
+		length = FileRecordSegmentHeaderExtendedLength
+	}
+	data := make([]byte, length)
+	header.MultiSectorHeader.marshal(data[0:8])
+	binary.LittleEndian.PutUint64(data[8:16], header.LogFileSequenceNumber)
+	binary.LittleEndian.PutUint16(data[16:18], header.SequenceNumber)
+	binary.LittleEndian.PutUint16(data[18:20], header.HardLinkCount)
+	binary.LittleEndian.PutUint16(data[20:22], header.FirstAttributeOffset)
+	binary.LittleEndian.PutUint16(data[22:24], uint16(header.Flags))
+	binary.LittleEndian.PutUint32(data[24:28], header.ActualSize)
+	binary.LittleEndian.PutUint32(data[28:32], header.AllocatedSize)
+	header.BaseFileRecordSegment.marshal(data[32:40])
+	binary.LittleEndian.PutUint16(data[40:42], header.NextAttributeID)
+	if header.Extended() {
+		binary.LittleEndian.PutUint16(data[42:44], header.reserved)
+		binary.LittleEndian.PutUint32(data[44:48], header.RecordNumber)
+	}
+	return data, nil
+}
@@ -0,0 +1,68 @@
+package filerecordflag
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// https://flatcap.org/linux-ntfs/ntfs/concepts/file_record.html
+
+// Flag is a file record segment header flag.
+type Flag uint16
+
+// File record segment header flags.
+const (
+	InUse       Flag = 0x0001 // FILE_RECORD_SEGMENT_IN_USE
+	Directory   Flag = 0x0002 // FILE_FILE_NAME_INDEX_PRESENT
+	ExtendIndex Flag = 0x0004 // Record is in the $Extend directory
+	ViewIndex   Flag = 0x0008 // Record has a view index, such as $Secure:$SII
+	KnownMask   Flag = InUse | Directory | ExtendIndex | ViewIndex
+	UnknownMask Flag = ^KnownMask
+)
+
+// Unmarshal unmarshals the little-endian binary representation
+// of file record segment header flags.
+//
+// The provided data must be at least 2 bytes long, or unmarshal will
+// panic.
+func Unmarshal(data []byte) Flag {
+	return Flag(binary.LittleEndian.Uint16(data[0:2]))
+}
+
+// String returns a description of the file record segment header flags.
+func (f Flag) String() string {
+	var flags []string
+
+	// Report known flags
+	if f&InUse != 0 {
+		flags = append(flags, "InUse")
+	}
+	if f&Directory != 0 {
+		flags = append(flags, "Directory")
+	}
+	if f&ExtendIndex != 0 {
+		flags = append(flags, "ExtendIndex")
+	}
+	if f&ViewIndex != 0 {
+		flags = append(flags, "ViewIndex")
+	}
+
+	// Report unknown flags
+	if f&UnknownMask != 0 {
+		for i := uint(0); i < 16; i++ {
+			q := Flag(1) << i
+			// Find flags that are present
+			if q&f == 0 {
+				continue
+			}
+			// Skip flags that we've already identified
+			if q&UnknownMask == 0 {
+				continue
+			}
+			flags = append(flags, fmt.Sprintf("%#04x", uint16(q)))
+		}
+	}
+
+	return strings.Join(flags, ",")
+}
@@ -14,11 +14,27 @@ type SegmentReference struct {
 	SequenceNumber        uint16
 }
 
+// NewSegmentReference returns a segment reference to the file record with
+// the given segment number and sequence number.
+func NewSegmentReference(number int64, sequence uint16) SegmentReference {
+	return SegmentReference{
+		SegmentNumberLowPart:  uint32(number),
+		SegmentNumberHighPart: uint16(number >> 32),
+		SequenceNumber:        sequence,
+	}
+}
+
 // IsZero returns true if the segment reference is zero.
 func (ref *SegmentReference) IsZero() bool {
 	return ref.SegmentNumberLowPart == 0 && ref.SegmentNumberHighPart == 0 && ref.SequenceNumber == 0
 }
 
+// SegmentNumber returns the 48-bit segment number of the reference, which
+// is the number of the file record it refers to.
+func (ref *SegmentReference) SegmentNumber() int64 {
+	return int64(ref.SegmentNumberHighPart)<<32 | int64(ref.SegmentNumberLowPart)
+}
+
 // UnmarshalBinary unmarshals the little-endian binary representation
 // of a segment reference into ref.
 //
@@ -33,5 +49,18 @@ func (ref *SegmentReference) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// MarshalBinary returns the little-endian binary representation of ref.
+func (ref *SegmentReference) MarshalBinary() ([]byte, error) {
+	data := make([]byte, SegmentReferenceLength)
+	ref.marshal(data)
+	return data, nil
+}
+
+func (ref *SegmentReference) marshal(data []byte) {
+	binary.LittleEndian.PutUint32(data[0:4], ref.SegmentNumberLowPart)
+	binary.LittleEndian.PutUint16(data[4:6], ref.SegmentNumberHighPart)
+	binary.LittleEndian.PutUint16(data[6:8], ref.SequenceNumber)
+}
+
 // FileReference is a reference to a file in the master file table.
 type FileReference = SegmentReference
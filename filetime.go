@@ -15,15 +15,35 @@ const (
 	// The offset between the windows epoch and unix epoch in 1-second intervals
 	unixtimeOffsetSeconds = 11644473600
 
-	// The offset between the windows epoch and unix epoch in 100-nanosecond intervals
-	unixtimeOffset100Nano = unixtimeOffsetSeconds * (int64(time.Second) / 100)
+	// The number of 100-nanosecond intervals in a second
+	fileTimeTicks = uint64(time.Second) / 100
 )
 
+// fileTimeEpoch is the earliest time that can be represented by a file time.
+var fileTimeEpoch = time.Unix(-unixtimeOffsetSeconds, 0).UTC()
+
+// unmarshalFileTime returns the time stored in data. A file time of zero
+// means the time was never set and is returned as the zero time.
 func unmarshalFileTime(data []byte) time.Time {
-	t := int64(binary.LittleEndian.Uint64(data)) // 100 nanosecond intervals since the windows epoch
-	t -= unixtimeOffset100Nano                   // converted to unixtime (in 100 nanoseconds)
-	t *= 100                                     // converted to unixtime (in nanoseconds)
-	return time.Unix(0, t).UTC()
+	t := binary.LittleEndian.Uint64(data) // 100 nanosecond intervals since the windows epoch
+	if t == 0 {
+		return time.Time{}
+	}
+	sec := int64(t/fileTimeTicks) - unixtimeOffsetSeconds // converted to unixtime (in seconds)
+	nsec := int64(t%fileTimeTicks) * 100                  // remainder (in nanoseconds)
+	return time.Unix(sec, nsec).UTC()
+}
+
+// marshalFileTime stores t in data. The zero time and times before the
+// windows epoch are stored as zero.
+func marshalFileTime(data []byte, t time.Time) {
+	if !t.After(fileTimeEpoch) {
+		binary.LittleEndian.PutUint64(data, 0)
+		return
+	}
+	ticks := uint64(t.Unix()+unixtimeOffsetSeconds) * fileTimeTicks
+	ticks += uint64(t.Nanosecond() / 100)
+	binary.LittleEndian.PutUint64(data, ticks)
 }
 
 func localTimeString(t time.Time) string {
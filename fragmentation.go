@@ -0,0 +1,241 @@
+package ntfs
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gentlemanautomaton/ntfs/attrtype"
+)
+
+// FileFragmentation describes the fragmentation of a file.
+type FileFragmentation struct {
+	File      FileReference
+	Name      string
+	Directory bool
+	Fragments int   // The number of discontiguous fragments of the file's streams
+	Clusters  int64 // The number of clusters allocated to the file's streams
+}
+
+// Fragmented returns true if the file has more than one fragment.
+func (f *FileFragmentation) Fragmented() bool {
+	return f.Fragments > 1
+}
+
+// FragmentationReport summarizes the fragmentation of a volume.
+type FragmentationReport struct {
+	Clusters           int64 // The number of clusters in the volume
+	Files              int   // The number of files with non-resident data
+	FragmentedFiles    int   // The number of files with more than one fragment
+	Fragments          int   // The total number of fragments of all files
+	AllocatedClusters  int64 // The number of clusters allocated to files
+	FragmentedClusters int64 // The number of clusters allocated to fragmented files
+	MFTFragments       int   // The number of fragments of the $MFT data
+	MFTClusters        int64 // The number of clusters allocated to the $MFT data
+	FreeClusters       int64 // The number of free clusters in $Bitmap
+	FreeExtents        int   // The number of discontiguous free space extents
+	LargestFreeExtent  int64 // The length of the largest free space extent in clusters
+	MostFragmented     []FileFragmentation
+}
+
+// Score returns the percentage of allocated clusters that belong to
+// fragmented files. It is comparable to the fragmented space reported by
+// the Windows defragmenter's analysis.
+func (report *FragmentationReport) Score() float64 {
+	if report.AllocatedClusters == 0 {
+		return 0
+	}
+	return float64(report.FragmentedClusters) / float64(report.AllocatedClusters) * 100
+}
+
+// AverageFragments returns the average number of fragments per file.
+func (report *FragmentationReport) AverageFragments() float64 {
+	if report.Files == 0 {
+		return 0
+	}
+	return float64(report.Fragments) / float64(report.Files)
+}
+
+// AverageFreeExtent returns the average length of a free space extent in
+// clusters.
+func (report *FragmentationReport) AverageFreeExtent() float64 {
+	if report.FreeExtents == 0 {
+		return 0
+	}
+	return float64(report.FreeClusters) / float64(report.FreeExtents)
+}
+
+// String returns a description of the report.
+func (report *FragmentationReport) String() string {
+	return fmt.Sprintf("%.0f%% fragmented (%d of %d files, %.2f fragments per file), $MFT: %d fragments, free space: %d extents",
+		report.Score(), report.FragmentedFiles, report.Files, report.AverageFragments(), report.MFTFragments, report.FreeExtents)
+}
+
+// AnalyzeFragmentation analyzes the fragmentation of every in-use file on
+// the volume and the fragmentation of its free space.
+//
+// The fragments of each file's $DATA streams, and of the $I30 index
+// allocation of directories, are counted. If each is not nil it is called
+// for every file with non-resident data. The report includes the top most
+// fragmented files. Standalone readers return ErrNoVolume.
+func (r *Reader) AnalyzeFragmentation(top int, each func(FileFragmentation)) (*FragmentationReport, error) {
+	if r.Standalone() {
+		return nil, ErrNoVolume
+	}
+	report := &FragmentationReport{
+		Clusters: r.boot.Clusters(),
+	}
+
+	err := r.Walk(func(file *File, err error) error {
+		if err != nil || !file.Header.InUse() || !file.Header.BaseFileRecordSegment.IsZero() {
+			return nil
+		}
+
+		frag := FileFragmentation{
+			File:      file.Reference(),
+			Name:      fragmentationName(file),
+			Directory: file.Header.Directory(),
+		}
+		for _, stream := range r.fragmentationStreams(file) {
+			extents, err := r.Extents(file, stream.code, stream.name)
+			if err != nil {
+				continue
+			}
+			fragments, clusters := countFragments(extents)
+			frag.Fragments += fragments
+			frag.Clusters += clusters
+		}
+		if frag.Clusters == 0 {
+			return nil
+		}
+
+		report.Files++
+		report.Fragments += frag.Fragments
+		report.AllocatedClusters += frag.Clusters
+		if frag.Fragmented() {
+			report.FragmentedFiles++
+			report.FragmentedClusters += frag.Clusters
+		}
+		if file.ID == RecordMFT {
+			if extents, err := r.Extents(file, attrtype.Data, ""); err == nil {
+				report.MFTFragments, report.MFTClusters = countFragments(extents)
+			}
+		}
+		if each != nil {
+			each(frag)
+		}
+		if top > 0 && frag.Fragmented() {
+			report.MostFragmented = appendMostFragmented(report.MostFragmented, frag, top)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bitmap, err := r.ClusterBitmap()
+	if err != nil {
+		return report, fmt.Errorf("unable to read the cluster bitmap: %v", err)
+	}
+	var run int64
+	for lcn := int64(0); lcn <= report.Clusters; lcn++ {
+		if lcn < report.Clusters && !bitmap.Bit(lcn) {
+			run++
+			continue
+		}
+		if run > 0 {
+			report.FreeClusters += run
+			report.FreeExtents++
+			if run > report.LargestFreeExtent {
+				report.LargestFreeExtent = run
+			}
+			run = 0
+		}
+	}
+
+	return report, nil
+}
+
+// fragmentationStream identifies a stream of a file.
+type fragmentationStream struct {
+	code attrtype.Code
+	name string
+}
+
+// fragmentationStreams returns the non-resident streams of file that count
+// toward its fragmentation, including those stored in extension records.
+func (r *Reader) fragmentationStreams(file *File) []fragmentationStream {
+	var streams []fragmentationStream
+	add := func(code attrtype.Code, name string) {
+		for _, s := range streams {
+			if s.code == code && s.name == name {
+				return
+			}
+		}
+		streams = append(streams, fragmentationStream{code: code, name: name})
+	}
+
+	entries, _ := r.AttributeList(file)
+	for _, entry := range entries {
+		if countsTowardFragmentation(entry.TypeCode, entry.AttributeName) {
+			add(entry.TypeCode, entry.AttributeName)
+		}
+	}
+	for a := range file.Attributes {
+		attr := &file.Attributes[a]
+		if !attr.Header.Resident() && countsTowardFragmentation(attr.Header.TypeCode, attr.Name) {
+			add(attr.Header.TypeCode, attr.Name)
+		}
+	}
+	return streams
+}
+
+// countsTowardFragmentation returns true if a stream with the given type
+// and name counts toward the fragmentation of its file.
+func countsTowardFragmentation(code attrtype.Code, name string) bool {
+	return code == attrtype.Data || (code == attrtype.IndexAllocation && name == FileNameIndex)
+}
+
+// countFragments returns the number of discontiguous fragments and the
+// number of allocated clusters in a series of extents. Sparse extents are
+// ignored.
+func countFragments(extents []Extent) (fragments int, clusters int64) {
+	next := int64(-1)
+	for _, e := range extents {
+		if e.Sparse {
+			continue
+		}
+		if e.LCN != next {
+			fragments++
+		}
+		next = e.LCN + e.Clusters
+		clusters += e.Clusters
+	}
+	return
+}
+
+// appendMostFragmented inserts frag into a list of at most top files that
+// is sorted by descending fragment count.
+func appendMostFragmented(list []FileFragmentation, frag FileFragmentation, top int) []FileFragmentation {
+	i := sort.Search(len(list), func(i int) bool {
+		return list[i].Fragments < frag.Fragments
+	})
+	if i >= top {
+		return list
+	}
+	if len(list) < top {
+		list = append(list, FileFragmentation{})
+	}
+	copy(list[i+1:], list[i:])
+	list[i] = frag
+	return list
+}
+
+// fragmentationName returns the preferred name of file.
+func fragmentationName(file *File) string {
+	names, err := unmarshalNames(file.Attributes)
+	if err != nil {
+		return ""
+	}
+	name, _ := PreferredName(names)
+	return name.Value
+}
@@ -16,3 +16,11 @@ func unmarshalGUID(data []byte) GUID {
 	g[6], g[7] = g[7], g[6]
 	return g
 }
+
+func marshalGUID(data []byte, g GUID) {
+	copy(data[0:16], g[:])
+	// Byte-order conversion from big-endian to little-endian
+	data[0], data[1], data[2], data[3] = g[3], g[2], g[1], g[0]
+	data[4], data[5] = g[5], g[4]
+	data[6], data[7] = g[7], g[6]
+}
@@ -0,0 +1,263 @@
+package ntfs
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gentlemanautomaton/ntfs/attrtype"
+)
+
+// https://flatcap.org/linux-ntfs/ntfs/concepts/index_header.html
+// https://flatcap.org/linux-ntfs/ntfs/concepts/index_entry.html
+// https://flatcap.org/linux-ntfs/ntfs/concepts/index_record.html
+
+// FileNameIndex is the name of the index attributes that hold the file
+// names of a directory.
+const FileNameIndex = "$I30"
+
+// IndexHeaderLength is the length of an index header in bytes.
+const IndexHeaderLength = 16
+
+// Index header flags.
+const (
+	indexHeaderLarge = 0x01 // The index has subnodes in an index allocation
+)
+
+// IndexHeader describes the entries of an index node. It is present in
+// $INDEX_ROOT attributes and in the index records of $INDEX_ALLOCATION
+// attributes.
+type IndexHeader struct {
+	EntriesOffset   uint32 //  0:4  Relative to the start of the index header
+	IndexLength     uint32 //  4:8  Relative to the start of the index header
+	AllocatedLength uint32 //  8:12 Relative to the start of the index header
+	Flags           uint8  // 12:13
+	reserved        [3]byte
+}
+
+// HasSubnodes returns true if the index node has subnodes stored in an index
+// allocation.
+func (header *IndexHeader) HasSubnodes() bool {
+	return header.Flags&indexHeaderLarge != 0
+}
+
+// UnmarshalBinary unmarshals the little-endian binary representation
+// of an index header into header.
+//
+// The provided data must be at least 16 bytes long.
+func (header *IndexHeader) UnmarshalBinary(data []byte) error {
+	if len(data) < IndexHeaderLength {
+		return ErrTruncatedData
+	}
+	header.EntriesOffset = binary.LittleEndian.Uint32(data[0:4])
+	header.IndexLength = binary.LittleEndian.Uint32(data[4:8])
+	header.AllocatedLength = binary.LittleEndian.Uint32(data[8:12])
+	header.Flags = data[12]
+	copy(header.reserved[:], data[13:16])
+	return nil
+}
+
+// Entries unmarshals the index entries described by header. The provided
+// data must begin with the index header.
+//
+// The terminating entry of the node is not included in the returned slice
+// unless it points to a subnode.
+func (header *IndexHeader) Entries(data []byte) ([]IndexEntry, error) {
+	end := int(header.IndexLength)
+	if end > len(data) {
+		return nil, ErrIndexOutOfBounds
+	}
+	var entries []IndexEntry
+	pos := int(header.EntriesOffset)
+	for pos < end {
+		var entry IndexEntry
+		if err := entry.UnmarshalBinary(data[pos:end]); err != nil {
+			return entries, err
+		}
+		if !entry.Last() || entry.HasSubnode() {
+			entries = append(entries, entry)
+		}
+		if entry.Last() {
+			break
+		}
+		if entry.Length == 0 {
+			return entries, ErrIndexEntryLengthInvalid
+		}
+		pos += int(entry.Length)
+	}
+	return entries, nil
+}
+
+// IndexEntryMinLength is the minimum length of an index entry in bytes.
+const IndexEntryMinLength = 16
+
+// Index entry flags.
+const (
+	indexEntryNode = 0x01 // The entry points to a subnode
+	indexEntryEnd  = 0x02 // The entry is the last in its node
+)
+
+// IndexEntry is an entry in an index node.
+type IndexEntry struct {
+	FileReference FileReference //  0:8
+	Length        uint16        //  8:10
+	KeyLength     uint16        // 10:12
+	Flags         uint16        // 12:14
+	reserved      uint16        // 14:16
+	Key           []byte        // The indexed attribute value, i.e. a $FILE_NAME
+	Subnode       VCN           // The VCN of the subnode if HasSubnode is true
+}
+
+// HasSubnode returns true if the index entry points to a subnode.
+func (entry *IndexEntry) HasSubnode() bool {
+	return entry.Flags&indexEntryNode != 0
+}
+
+// Last returns true if the index entry is the last entry in its node. The
+// last entry doesn't have a key.
+func (entry *IndexEntry) Last() bool {
+	return entry.Flags&indexEntryEnd != 0
+}
+
+// FileName unmarshals the key of a file name index entry.
+func (entry *IndexEntry) FileName() (FileName, error) {
+	var fn FileName
+	err := fn.UnmarshalBinary(entry.Key)
+	return fn, err
+}
+
+// UnmarshalBinary unmarshals the little-endian binary representation
+// of an index entry into entry.
+//
+// The provided data must be at least 16 bytes long.
+func (entry *IndexEntry) UnmarshalBinary(data []byte) error {
+	if len(data) < IndexEntryMinLength {
+		return ErrTruncatedData
+	}
+	if err := entry.FileReference.UnmarshalBinary(data[0:8]); err != nil {
+		return err
+	}
+	entry.Length = binary.LittleEndian.Uint16(data[8:10])
+	entry.KeyLength = binary.LittleEndian.Uint16(data[10:12])
+	entry.Flags = binary.LittleEndian.Uint16(data[12:14])
+	entry.reserved = binary.LittleEndian.Uint16(data[14:16])
+	if int(entry.Length) > len(data) {
+		return ErrIndexOutOfBounds
+	}
+	entry.Key = nil
+	if entry.KeyLength > 0 && !entry.Last() {
+		end := IndexEntryMinLength + int(entry.KeyLength)
+		if end > int(entry.Length) {
+			return ErrIndexOutOfBounds
+		}
+		entry.Key = make([]byte, entry.KeyLength)
+		copy(entry.Key, data[IndexEntryMinLength:end])
+	}
+	entry.Subnode = 0
+	if entry.HasSubnode() {
+		if entry.Length < IndexEntryMinLength+8 {
+			return ErrIndexOutOfBounds
+		}
+		entry.Subnode = VCN(binary.LittleEndian.Uint64(data[entry.Length-8 : entry.Length]))
+	}
+	return nil
+}
+
+// IndexRecordHeaderLength is the length of an index record header in bytes,
+// excluding its index header.
+const IndexRecordHeaderLength = 24
+
+// IndexRecordSignature is the multi-sector header signature of index
+// records.
+var IndexRecordSignature = [4]byte{'I', 'N', 'D', 'X'}
+
+// IndexRecordHeader is present at the start of each index record in an
+// $INDEX_ALLOCATION attribute.
+type IndexRecordHeader struct {
+	MultiSectorHeader
+	LogFileSequenceNumber uint64
+	VCN                   VCN
+	IndexHeader
+}
+
+// UnmarshalBinary unmarshals the little-endian binary representation
+// of an index record header into header.
+//
+// The provided data must be at least 40 bytes long.
+func (header *IndexRecordHeader) UnmarshalBinary(data []byte) error {
+	if len(data) < IndexRecordHeaderLength+IndexHeaderLength {
+		return ErrTruncatedData
+	}
+	if err := header.MultiSectorHeader.UnmarshalBinary(data[0:8]); err != nil {
+		return err
+	}
+	header.LogFileSequenceNumber = binary.LittleEndian.Uint64(data[8:16])
+	header.VCN = VCN(binary.LittleEndian.Uint64(data[16:24]))
+	return header.IndexHeader.UnmarshalBinary(data[24:40])
+}
+
+// IndexEntries returns all of the entries in the index with the given
+// name, which is typically FileNameIndex. Entries from the $INDEX_ROOT
+// attribute are returned first, followed by entries from each index
+// record in the $INDEX_ALLOCATION attribute that is marked in use.
+func (r *Reader) IndexEntries(file *File, name string) ([]IndexEntry, error) {
+	rootAttr, ok := file.NamedAttribute(attrtype.IndexRoot, name)
+	if !ok {
+		return nil, ErrAttributeMissing
+	}
+	var root IndexRoot
+	if err := root.UnmarshalBinary(rootAttr.ResidentValue); err != nil {
+		return nil, err
+	}
+	entries, err := root.Node.Entries(rootAttr.ResidentValue[IndexRootHeaderLength:])
+	if err != nil {
+		return entries, err
+	}
+
+	allocAttr, ok := file.NamedAttribute(attrtype.IndexAllocation, name)
+	if !ok {
+		return entries, nil
+	}
+
+	var bitmap Bitmap
+	if bitmapAttr, ok := file.NamedAttribute(attrtype.Bitmap, name); ok {
+		if bitmap, err = r.ReadAttribute(bitmapAttr); err != nil {
+			return entries, err
+		}
+	}
+
+	alloc, err := r.OpenAttribute(allocAttr)
+	if err != nil {
+		return entries, err
+	}
+
+	size := int64(root.BytesPerIndexRecord)
+	if size <= 0 {
+		return entries, ErrInvalidIndexRecordSize
+	}
+	block := make([]byte, size)
+	for i := int64(0); i*size < alloc.Size(); i++ {
+		if bitmap != nil && !bitmap.Bit(i) {
+			continue
+		}
+		if _, err := alloc.ReadAt(block, i*size); err != nil {
+			return entries, fmt.Errorf("unable to read index record %d: %v", i, err)
+		}
+		var header IndexRecordHeader
+		if err := header.UnmarshalBinary(block); err != nil {
+			return entries, fmt.Errorf("unable to parse index record %d: %v", i, err)
+		}
+		if header.Signature != IndexRecordSignature {
+			return entries, fmt.Errorf("unable to parse index record %d: %v", i, ErrInvalidIndexSignature)
+		}
+		if err := header.ApplyFixups(block); err != nil {
+			return entries, fmt.Errorf("unable to apply fixups to index record %d: %v", i, err)
+		}
+		blockEntries, err := header.IndexHeader.Entries(block[IndexRecordHeaderLength:])
+		entries = append(entries, blockEntries...)
+		if err != nil {
+			return entries, fmt.Errorf("unable to parse entries of index record %d: %v", i, err)
+		}
+	}
+
+	return entries, nil
+}
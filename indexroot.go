@@ -8,9 +8,13 @@ import (
 	"github.com/gentlemanautomaton/ntfs/collation"
 )
 
+// IndexRootHeaderLength is the length of an $INDEX_ROOT attribute header in
+// bytes, excluding its index header.
+const IndexRootHeaderLength = 16
+
 // IndexRootMinLength is the minimum length of an $INDEX_ROOT attribute
 // in bytes.
-const IndexRootMinLength = 16
+const IndexRootMinLength = IndexRootHeaderLength + IndexHeaderLength
 
 // IndexRoot stores $INDEX_ROOT attribute information.
 type IndexRoot struct {
@@ -19,6 +23,7 @@ type IndexRoot struct {
 	BytesPerIndexRecord  uint32         //  8:12
 	BlocksPerIndexRecord uint8          // 12:13 In sectors if BPIR < ClusterSize, otherwise clusters
 	reserved1            [3]byte        // 13:15
+	Node                 IndexHeader    // 16:32 Describes the entries that follow
 }
 
 // UnmarshalBinary unmarshals the little-endian binary representation
@@ -34,7 +39,7 @@ func (index *IndexRoot) UnmarshalBinary(data []byte) error {
 	index.reserved1[0] = data[13]
 	index.reserved1[1] = data[14]
 	index.reserved1[2] = data[15]
-	return nil
+	return index.Node.UnmarshalBinary(data[16:32])
 }
 
 // String returns a description of the volume information.
@@ -0,0 +1,194 @@
+package ldm
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Layout describes how the data of a volume is arranged on its disks.
+type Layout int
+
+// Volume layouts.
+const (
+	Simple   Layout = iota // A single partition
+	Spanned                // Partitions joined end to end
+	Striped                // Partitions interleaved in stripes (RAID-0)
+	Mirrored               // Identical copies (RAID-1)
+	RAID5                  // Striped with parity
+)
+
+// String returns a string representation of the layout.
+func (l Layout) String() string {
+	switch l {
+	case Simple:
+		return "Simple"
+	case Spanned:
+		return "Spanned"
+	case Striped:
+		return "Striped"
+	case Mirrored:
+		return "Mirrored"
+	case RAID5:
+		return "RAID-5"
+	default:
+		return fmt.Sprintf("Layout %d", int(l))
+	}
+}
+
+// Database is the content of an LDM database. Every disk in a disk group
+// holds a copy of the database describing the whole group.
+type Database struct {
+	VMDB       VMDB
+	DiskGroup  DiskGroup
+	Disks      []Disk
+	Volumes    []Volume
+	Components []Component
+	Partitions []Partition
+}
+
+// Disk returns the disk record with the given object ID.
+func (db *Database) Disk(id uint64) (Disk, bool) {
+	for _, d := range db.Disks {
+		if d.ID == id {
+			return d, true
+		}
+	}
+	return Disk{}, false
+}
+
+// VolumeComponents returns the components of v.
+func (db *Database) VolumeComponents(v Volume) []Component {
+	var components []Component
+	for _, c := range db.Components {
+		if c.VolumeID == v.ID {
+			components = append(components, c)
+		}
+	}
+	return components
+}
+
+// ComponentPartitions returns the partitions of c in order of their
+// column and their offset within it.
+func (db *Database) ComponentPartitions(c Component) []Partition {
+	var partitions []Partition
+	for _, p := range db.Partitions {
+		if p.ComponentID == c.ID {
+			partitions = append(partitions, p)
+		}
+	}
+	sort.SliceStable(partitions, func(i, j int) bool {
+		if partitions[i].Index != partitions[j].Index {
+			return partitions[i].Index < partitions[j].Index
+		}
+		return partitions[i].VolumeOffset < partitions[j].VolumeOffset
+	})
+	return partitions
+}
+
+// Layout returns the layout of v.
+func (db *Database) Layout(v Volume) Layout {
+	components := db.VolumeComponents(v)
+	switch {
+	case v.Type == "raid5":
+		return RAID5
+	case len(components) > 1:
+		return Mirrored
+	case len(components) == 0:
+		return Simple
+	}
+	switch components[0].Type {
+	case StripedComponent:
+		return Striped
+	case RAIDComponent:
+		return RAID5
+	}
+	if len(db.ComponentPartitions(components[0])) > 1 {
+		return Spanned
+	}
+	return Simple
+}
+
+// readDatabase reads the LDM database that starts at offset and spans size
+// sectors.
+func readDatabase(r io.ReaderAt, offset, size, sectorSize int64) (*Database, TOCBlock, error) {
+	var toc TOCBlock
+	err := ErrInvalidTOCBlock
+	sector := make([]byte, sectorSize)
+	for _, s := range []int64{1, 2, size - 3, size - 2} {
+		if _, err = r.ReadAt(sector, offset+s*sectorSize); err != nil {
+			continue
+		}
+		if err = toc.UnmarshalBinary(sector); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, toc, err
+	}
+	config, ok := toc.Region(ConfigRegion)
+	if !ok || int64(config.Start+config.Size) > size {
+		return nil, toc, fmt.Errorf("%w: missing or invalid config region", ErrInvalidTOCBlock)
+	}
+
+	db := new(Database)
+	start := offset + int64(config.Start)*sectorSize
+	if _, err := r.ReadAt(sector, start); err != nil {
+		return nil, toc, fmt.Errorf("failed to read VMDB: %w", err)
+	}
+	if err := db.VMDB.UnmarshalBinary(sector); err != nil {
+		return nil, toc, err
+	}
+
+	// Read the VBLK records
+	recordSize := int64(db.VMDB.RecordSize)
+	end := int64(db.VMDB.LastSequence) * recordSize
+	if limit := int64(config.Size) * sectorSize; end > limit {
+		end = limit
+	}
+	data := make([]byte, end)
+	if _, err := r.ReadAt(data, start); err != nil {
+		return nil, toc, fmt.Errorf("failed to read VBLK records: %w", err)
+	}
+
+	fragments := make(map[uint32][]vblkFragment)
+	for pos := int64(db.VMDB.FirstRecordOffset); pos+recordSize <= end; pos += recordSize {
+		var f vblkFragment
+		if f.UnmarshalBinary(data[pos:pos+recordSize]) != nil || f.Count == 0 {
+			continue // Unused
+		}
+		if f.Count == 1 {
+			if err := db.parseRecord(data[pos : pos+recordSize]); err != nil {
+				return nil, toc, err
+			}
+			continue
+		}
+		fragments[f.Group] = append(fragments[f.Group], f)
+	}
+
+	// Assemble records that span multiple fragments
+	groups := make([]uint32, 0, len(fragments))
+	for group := range fragments {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i] < groups[j] })
+	for _, group := range groups {
+		parts := fragments[group]
+		sort.Slice(parts, func(i, j int) bool { return parts[i].Index < parts[j].Index })
+		if len(parts) != int(parts[0].Count) {
+			return nil, toc, fmt.Errorf("%w: record group %d has %d of %d fragments", ErrInvalidVBLK, group, len(parts), parts[0].Count)
+		}
+		record := make([]byte, vblkHeaderLength, vblkHeaderLength+len(parts)*len(parts[0].Data))
+		for i, part := range parts {
+			if int(part.Index) != i {
+				return nil, toc, fmt.Errorf("%w: record group %d is missing fragment %d", ErrInvalidVBLK, group, i)
+			}
+			record = append(record, part.Data...)
+		}
+		if err := db.parseRecord(record); err != nil {
+			return nil, toc, err
+		}
+	}
+
+	return db, toc, nil
+}
@@ -0,0 +1,53 @@
+package ldm
+
+import "errors"
+
+var (
+	// ErrTruncatedData is returned when attempting to read data from a buffer
+	// or reader with insufficient data.
+	ErrTruncatedData = errors.New("insufficient or truncated data")
+
+	// ErrNoPrivateHeader is returned when a disk does not contain a valid
+	// LDM private header, typically because it is not a dynamic disk.
+	ErrNoPrivateHeader = errors.New("no LDM private header found")
+
+	// ErrInvalidPrivateHeader is returned when attempting to parse an LDM
+	// private header with an invalid signature or configuration location.
+	ErrInvalidPrivateHeader = errors.New("invalid LDM private header")
+
+	// ErrInvalidTOCBlock is returned when an LDM database does not contain a
+	// valid table of contents block.
+	ErrInvalidTOCBlock = errors.New("invalid LDM table of contents block")
+
+	// ErrInvalidVMDB is returned when attempting to parse an LDM volume
+	// manager database header with an invalid signature or record size.
+	ErrInvalidVMDB = errors.New("invalid LDM volume manager database header")
+
+	// ErrInvalidVBLK is returned when attempting to parse an LDM database
+	// record that is malformed.
+	ErrInvalidVBLK = errors.New("invalid LDM database record")
+
+	// ErrUnsupportedVersion is returned when an LDM structure has a version
+	// that is not supported.
+	ErrUnsupportedVersion = errors.New("unsupported LDM version")
+
+	// ErrDiskGroupMismatch is returned when assembling a disk group from
+	// disks that belong to different groups.
+	ErrDiskGroupMismatch = errors.New("disks belong to different disk groups")
+
+	// ErrMissingDisk is returned when opening a volume that has data on a
+	// disk that has not been provided.
+	ErrMissingDisk = errors.New("volume has data on a missing disk")
+
+	// ErrUnsupportedVolume is returned when opening a volume with a layout
+	// that is not supported, such as RAID-5.
+	ErrUnsupportedVolume = errors.New("unsupported volume layout")
+
+	// ErrNegativeOffset is returned when attempting to read a volume at a
+	// negative offset.
+	ErrNegativeOffset = errors.New("negative offset")
+
+	// ErrInvalidVolume is returned when the partitions of a volume don't
+	// describe a consistent layout.
+	ErrInvalidVolume = errors.New("volume has an inconsistent layout")
+)
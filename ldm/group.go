@@ -0,0 +1,130 @@
+package ldm
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+)
+
+// Group is a dynamic disk group assembled from its member disks.
+type Group struct {
+	// Database is the most recent copy of the LDM database held by the
+	// members of the group.
+	Database *Database
+
+	members map[uuid.UUID]*Member
+}
+
+// NewGroup assembles a disk group from members. All of the members must
+// belong to the same disk group, but members that hold no data for a
+// volume may be omitted when reading it.
+func NewGroup(members ...*Member) (*Group, error) {
+	if len(members) == 0 {
+		return nil, fmt.Errorf("%w: no members", ErrMissingDisk)
+	}
+	g := &Group{members: make(map[uuid.UUID]*Member)}
+	for _, m := range members {
+		if m.Header.DiskGroupGUID != members[0].Header.DiskGroupGUID {
+			return nil, ErrDiskGroupMismatch
+		}
+		g.members[m.Header.DiskGUID] = m
+		if g.Database == nil || m.Database.VMDB.CommittedSequence > g.Database.VMDB.CommittedSequence {
+			g.Database = m.Database
+		}
+	}
+	return g, nil
+}
+
+// Volumes returns the volumes of the disk group.
+func (g *Group) Volumes() []Volume {
+	return g.Database.Volumes
+}
+
+// MissingDisks returns the disks of the group that are not members.
+func (g *Group) MissingDisks() []Disk {
+	var missing []Disk
+	for _, d := range g.Database.Disks {
+		if _, ok := g.members[d.GUID]; !ok {
+			missing = append(missing, d)
+		}
+	}
+	return missing
+}
+
+// OpenVolume returns a reader for the contents of v that can be passed to
+// ntfs.NewReader.
+//
+// Mirrored volumes are read from the first copy whose disks are all
+// members. If a disk holding data for the volume is missing ErrMissingDisk
+// is returned. RAID-5 volumes are not supported.
+func (g *Group) OpenVolume(v Volume) (*io.SectionReader, error) {
+	components := g.Database.VolumeComponents(v)
+	if len(components) == 0 {
+		return nil, fmt.Errorf("%w: volume %s has no components", ErrInvalidVolume, v.Name)
+	}
+
+	var err error
+	for _, c := range components {
+		var r io.ReaderAt
+		var sectorSize int64
+		if r, sectorSize, err = g.openComponent(c); err == nil {
+			return io.NewSectionReader(r, 0, int64(v.Size)*sectorSize), nil
+		}
+	}
+	return nil, fmt.Errorf("volume %s: %w", v.Name, err)
+}
+
+// openComponent returns a reader for the contents of c and the sector size
+// of its disks.
+func (g *Group) openComponent(c Component) (io.ReaderAt, int64, error) {
+	partitions := g.Database.ComponentPartitions(c)
+	if len(partitions) == 0 {
+		return nil, 0, fmt.Errorf("%w: component %s has no partitions", ErrInvalidVolume, c.Name)
+	}
+
+	var sectorSize int64
+	extents := make([]extent, len(partitions))
+	for i, p := range partitions {
+		d, ok := g.Database.Disk(p.DiskID)
+		if !ok {
+			return nil, 0, fmt.Errorf("%w: partition %s refers to unknown disk %d", ErrInvalidVolume, p.Name, p.DiskID)
+		}
+		m, ok := g.members[d.GUID]
+		if !ok {
+			return nil, 0, fmt.Errorf("%w: %s (%s)", ErrMissingDisk, d.Name, d.GUID)
+		}
+		if sectorSize == 0 {
+			sectorSize = m.SectorSize
+		} else if sectorSize != m.SectorSize {
+			return nil, 0, fmt.Errorf("%w: disks have different sector sizes", ErrInvalidVolume)
+		}
+		extents[i] = extent{
+			Offset: int64(p.VolumeOffset) * sectorSize,
+			Length: int64(p.Size) * sectorSize,
+			Base:   int64(m.Header.LogicalDiskStart+p.Start) * sectorSize,
+			r:      m.r,
+		}
+	}
+
+	switch c.Type {
+	case SpannedComponent:
+		r, err := newConcatReader(extents)
+		return r, sectorSize, err
+	case StripedComponent:
+		if c.Columns == 0 || c.Columns > uint64(len(partitions)) || c.StripeSize == 0 {
+			return nil, 0, fmt.Errorf("%w: component %s has %d columns with a stripe size of %d", ErrInvalidVolume, c.Name, c.Columns, c.StripeSize)
+		}
+		columns := make([][]extent, c.Columns)
+		for i, p := range partitions {
+			if p.Index >= c.Columns {
+				return nil, 0, fmt.Errorf("%w: partition %s is in column %d of %d", ErrInvalidVolume, p.Name, p.Index, c.Columns)
+			}
+			columns[p.Index] = append(columns[p.Index], extents[i])
+		}
+		r, err := newStripeReader(columns, int64(c.StripeSize)*sectorSize)
+		return r, sectorSize, err
+	default:
+		return nil, 0, fmt.Errorf("%w: %s component %s", ErrUnsupportedVolume, c.Type, c.Name)
+	}
+}
@@ -0,0 +1,88 @@
+// Package ldm reads the volumes of Windows dynamic disks.
+//
+// Dynamic disks are described by the Logical Disk Manager (LDM) database,
+// which is kept in the last megabyte of MBR disks and in the LDM metadata
+// partition of GPT disks. Simple, spanned, striped and mirrored volumes
+// can be read by assembling the disks of a disk group with NewGroup.
+package ldm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gentlemanautomaton/ntfs/disk"
+	"github.com/gentlemanautomaton/ntfs/mspart"
+)
+
+// defaultSectorSize is the sector size assumed when a disk's partition
+// table can't be read.
+const defaultSectorSize = 512
+
+// Member is a dynamic disk that belongs to a disk group.
+type Member struct {
+	Header     PrivateHeader
+	TOC        TOCBlock
+	Database   *Database
+	SectorSize int64
+
+	r io.ReaderAt
+}
+
+// Open reads the LDM private header and database of a dynamic disk image
+// of the given size.
+//
+// If the disk does not contain an LDM private header ErrNoPrivateHeader is
+// returned.
+func Open(r io.ReaderAt, size int64) (*Member, error) {
+	m := &Member{r: r, SectorSize: defaultSectorSize}
+
+	// Locate the private header
+	offset := PrivateHeaderSector * m.SectorSize
+	if layout, err := disk.Read(r, size); err == nil {
+		m.SectorSize = layout.SectorSize
+		offset = PrivateHeaderSector * m.SectorSize
+		for _, p := range layout.Partitions {
+			if p.Scheme == disk.GPT && p.GPTType == mspart.LDMMetadata {
+				offset = p.Offset + p.Length - m.SectorSize
+			}
+		}
+	}
+
+	sector := make([]byte, m.SectorSize)
+	if _, err := r.ReadAt(sector, offset); err != nil {
+		return nil, fmt.Errorf("failed to read LDM private header: %w", err)
+	}
+	if err := m.Header.UnmarshalBinary(sector); err != nil {
+		if errors.Is(err, ErrInvalidPrivateHeader) && m.Header.Signature != PrivateHeaderSignature {
+			return nil, ErrNoPrivateHeader
+		}
+		return nil, err
+	}
+	if err := m.Header.Validate(); err != nil {
+		return nil, err
+	}
+
+	start := int64(m.Header.ConfigStart) * m.SectorSize
+	length := int64(m.Header.ConfigSize)
+	if start+length*m.SectorSize > size {
+		return nil, fmt.Errorf("%w: database lies beyond the end of the disk", ErrInvalidPrivateHeader)
+	}
+	db, toc, err := readDatabase(r, start, length, m.SectorSize)
+	if err != nil {
+		return nil, err
+	}
+	m.Database, m.TOC = db, toc
+
+	return m, nil
+}
+
+// Disk returns the disk record of the member in its database.
+func (m *Member) Disk() (Disk, bool) {
+	for _, d := range m.Database.Disks {
+		if d.GUID == m.Header.DiskGUID {
+			return d, true
+		}
+	}
+	return Disk{}, false
+}
@@ -0,0 +1,561 @@
+package ldm
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gentlemanautomaton/ntfs/mspart"
+	"github.com/google/uuid"
+)
+
+// zeros returns the hex encoding of n zero bytes.
+func zeros(n int) string {
+	return strings.Repeat("00", n)
+}
+
+// mustDecode returns the bytes of the hex string s.
+func mustDecode(s string) []byte {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// The test data follows the layout written by Windows for a disk group
+// named WIN-DG0.
+var (
+	testDiskGUID      = uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	testDisk2GUID     = uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	testHostGUID      = uuid.MustParse("33333333-3333-3333-3333-333333333333")
+	testDiskGroupGUID = uuid.MustParse("aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee")
+	testVolumeGUID    = uuid.MustParse("bbbbbbbb-cccc-dddd-eeee-ffffffffffff")
+
+	testPrivateHeader = "5052495648454144" + "00000001" + "0002000c" + "01d5a3b1c2d3e4f5" + zeros(0x18) +
+		"31313131313131312d313131312d313131312d313131312d313131313131313131313131" + zeros(0x1C) +
+		"33333333333333332d333333332d333333332d333333332d333333333333333333333333" + zeros(0x1C) +
+		"61616161616161612d626262622d636363632d646464642d656565656565656565656565" + zeros(0x1C) +
+		"57494e2d444730" + zeros(0x19) + zeros(0x0B) +
+		"000000000000003f" + "0000000000001000" + "0000000000001040" + "0000000000000800" +
+		"0000000000000002" + "0000000000000001" + "00000001" + "00000001" +
+		"00000000000005c9" + "00000000000000e0" + "12345678"
+	testTOCBlock = "544f43424c4f434b" + "00000001" + zeros(0x18) +
+		"636f6e6669670000" + "0000" + "0000000000000004" + "0000000000000028" + zeros(8) +
+		"6c6f670000000000" + "0000" + "000000000000002c" + "0000000000000010" + zeros(8)
+	testVMDB = "564d4442" + "0000000c" + "00000080" + "00000200" + "0000" + "0004000a" +
+		"57494e2d444730" + zeros(0x18) +
+		"61616161616161612d626262622d636363632d646464642d656565656565656565656565" + zeros(0x1C) +
+		"000000000000000a" + "000000000000000a"
+
+	// Records start at offset 0x10 of their VBLK and must be preceded by
+	// a fragment header.
+	testFragmentHeader  = "56424c4b" + "00000004" + "00000001" + "0000" + "0001"
+	testVolumeFields    = "0104" + "07566f6c756d6531" + "0367656e" + "00" + "414354495645" + zeros(8) + "01000400" + "0000aa" + "0101" + zeros(16) + "025000" + zeros(4) + "07" + "bbbbbbbbccccddddeeeeffffffffffff"
+	testVolumeRecord    = "0000aa51" + "0000005c" + testVolumeFields + "03696431" + "03696432" + "025000" + "02453a"
+	testComponentRecord = "00001032" + "00000032" + "0105" + "0a566f6c756d65312d3031" + "06414354495645" + "01" + zeros(4) + "0102" + zeros(16) + "0104" + "00" + "0180" + "0102"
+	testPartitionRecord = "00000833" + "00000030" + "0106" + "084469736b312d3031" + zeros(12) + "0000000000000100" + "0000000000000000" + "022800" + "0105" + "0102" + "0101"
+	testDiskRecord3     = "00000034" + "0000002d" + "0102" + "054469736b31" + "2431313131313131312d313131312d313131312d313131312d313131313131313131313131"
+	testDiskRecord4     = "00000044" + "00000018" + "0103" + "054469736b32" + "22222222222222222222222222222222"
+	testDiskGroupRecord = "00000045" + "0000002a" + "0101" + "0757494e2d444730" + "aaaaaaaabbbbccccddddeeeeeeeeeeee" + zeros(16)
+)
+
+var (
+	testVolume = Volume{
+		ID:            4,
+		Name:          "Volume1",
+		Type:          "gen",
+		State:         "ACTIVE",
+		Components:    1,
+		Size:          0x5000,
+		PartitionType: mspart.IFS,
+		GUID:          testVolumeGUID,
+		DriveHint:     "E:",
+	}
+	testComponent = Component{
+		ID:         5,
+		Name:       "Volume1-01",
+		State:      "ACTIVE",
+		Type:       StripedComponent,
+		Partitions: 2,
+		VolumeID:   4,
+		StripeSize: 0x80,
+		Columns:    2,
+	}
+	testPartition = Partition{
+		ID:          6,
+		Name:        "Disk1-01",
+		Start:       0x100,
+		Size:        0x2800,
+		ComponentID: 5,
+		DiskID:      2,
+		Index:       1,
+	}
+	testDisk3     = Disk{ID: 2, Name: "Disk1", GUID: testDiskGUID}
+	testDisk4     = Disk{ID: 3, Name: "Disk2", GUID: testDisk2GUID}
+	testDiskGroup = DiskGroup{ID: 1, Name: "WIN-DG0"}
+)
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		value encoding.BinaryUnmarshaler
+		data  string
+		want  interface{}
+		err   error
+	}{
+		{"PrivateHeader", &PrivateHeader{}, testPrivateHeader, &PrivateHeader{
+			Signature:        PrivateHeaderSignature,
+			Sequence:         1,
+			VersionMajor:     2,
+			VersionMinor:     12,
+			Timestamp:        0x01d5a3b1c2d3e4f5,
+			DiskGUID:         testDiskGUID,
+			HostGUID:         testHostGUID,
+			DiskGroupGUID:    testDiskGroupGUID,
+			DiskGroupName:    "WIN-DG0",
+			LogicalDiskStart: 0x3f,
+			LogicalDiskSize:  0x1000,
+			ConfigStart:      0x1040,
+			ConfigSize:       0x800,
+			TOCCount:         2,
+			TOCSize:          1,
+			ConfigCount:      1,
+			LogCount:         1,
+			ConfigLength:     0x5c9,
+			LogLength:        0xe0,
+			DiskSignature:    0x12345678,
+		}, nil},
+		{"PrivateHeaderTruncated", &PrivateHeader{}, testPrivateHeader[:len(testPrivateHeader)-2], nil, ErrTruncatedData},
+		{"PrivateHeaderSignature", &PrivateHeader{}, "00" + testPrivateHeader[2:], nil, ErrInvalidPrivateHeader},
+		{"PrivateHeaderDiskGUID", &PrivateHeader{}, testPrivateHeader[:0x60] + "78" + testPrivateHeader[0x62:], nil, ErrInvalidPrivateHeader},
+		{"TOCBlock", &TOCBlock{}, testTOCBlock, &TOCBlock{
+			Signature: TOCBlockSignature,
+			Sequence:  1,
+			Regions: [2]TOCRegion{
+				{Name: ConfigRegion, Start: 4, Size: 0x28},
+				{Name: LogRegion, Start: 0x2c, Size: 0x10},
+			},
+		}, nil},
+		{"TOCBlockTruncated", &TOCBlock{}, testTOCBlock[:len(testTOCBlock)-2], nil, ErrTruncatedData},
+		{"TOCBlockSignature", &TOCBlock{}, "00" + testTOCBlock[2:], nil, ErrInvalidTOCBlock},
+		{"VMDB", &VMDB{}, testVMDB, &VMDB{
+			Signature:         VMDBSignature,
+			LastSequence:      0x0c,
+			RecordSize:        0x80,
+			FirstRecordOffset: 0x200,
+			VersionMajor:      4,
+			VersionMinor:      10,
+			DiskGroupName:     "WIN-DG0",
+			DiskGroupGUID:     testDiskGroupGUID,
+			CommittedSequence: 0x0a,
+			PendingSequence:   0x0a,
+		}, nil},
+		{"VMDBTruncated", &VMDB{}, testVMDB[:len(testVMDB)-2], nil, ErrTruncatedData},
+		{"VMDBSignature", &VMDB{}, "00" + testVMDB[2:], nil, ErrInvalidVMDB},
+		{"VMDBVersion", &VMDB{}, testVMDB[:0x28] + "000b" + testVMDB[0x2C:], nil, ErrUnsupportedVersion},
+		{"VMDBRecordSize", &VMDB{}, testVMDB[:0x10] + "00000010" + testVMDB[0x18:], nil, ErrInvalidVMDB},
+		{"VBLK", &vblkFragment{}, testFragmentHeader + testDiskRecord4, &vblkFragment{
+			Sequence: 4,
+			Group:    1,
+			Index:    0,
+			Count:    1,
+			Data:     mustDecode(testDiskRecord4),
+		}, nil},
+		{"VBLKTruncated", &vblkFragment{}, testFragmentHeader[:len(testFragmentHeader)-2], nil, ErrTruncatedData},
+		{"VBLKSignature", &vblkFragment{}, "00" + testFragmentHeader[2:] + testDiskRecord4, nil, ErrInvalidVBLK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := hex.DecodeString(test.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = test.value.UnmarshalBinary(data)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+			if err == nil && !reflect.DeepEqual(test.value, test.want) {
+				t.Errorf("got %+v, want %+v", test.value, test.want)
+			}
+		})
+	}
+}
+
+func TestParseRecord(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want Database
+		err  error
+	}{
+		{"Volume", testVolumeRecord, Database{Volumes: []Volume{testVolume}}, nil},
+		{"VolumeDriveOnly", "00000251" + "00000051" + testVolumeFields + "02453a", Database{Volumes: []Volume{testVolume}}, nil},
+		{"VolumeNoFlags", "00000051" + "0000004e" + testVolumeFields, Database{Volumes: []Volume{func() Volume {
+			v := testVolume
+			v.DriveHint = ""
+			return v
+		}()}}, nil},
+		{"VolumeTruncated", testVolumeRecord[:len(testVolumeRecord)-6], Database{}, ErrInvalidVBLK},
+		{"Component", testComponentRecord, Database{Components: []Component{testComponent}}, nil},
+		{"ComponentSpanned", "00000032" + "0000002e" + "0105" + "0a566f6c756d65312d3031" + "06414354495645" + "02" + zeros(4) + "0102" + zeros(16) + "0104" + "00", Database{Components: []Component{func() Component {
+			c := testComponent
+			c.Type, c.StripeSize, c.Columns = SpannedComponent, 0, 0
+			return c
+		}()}}, nil},
+		{"Partition", testPartitionRecord, Database{Partitions: []Partition{testPartition}}, nil},
+		{"PartitionLongNumber", testPartitionRecord[:len(testPartitionRecord)-4] + "09" + zeros(9), Database{}, ErrInvalidVBLK},
+		{"Disk3", testDiskRecord3, Database{Disks: []Disk{testDisk3}}, nil},
+		{"Disk3GUID", testDiskRecord3[:len(testDiskRecord3)-2] + "78", Database{}, ErrInvalidVBLK},
+		{"Disk4", testDiskRecord4, Database{Disks: []Disk{testDisk4}}, nil},
+		{"DiskGroup", testDiskGroupRecord, Database{DiskGroup: testDiskGroup}, nil},
+		{"Unknown", "00000099" + "00000004" + "0107" + "00", Database{}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := hex.DecodeString(testFragmentHeader + test.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var db Database
+			if err := db.parseRecord(data); !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+			if !reflect.DeepEqual(db, test.want) {
+				t.Errorf("got %+v, want %+v", db, test.want)
+			}
+		})
+	}
+}
+
+// testDatabaseSectors is the number of sectors in each test database.
+const testDatabaseSectors = 64
+
+// testDatabase returns an LDM database with 512-byte sectors holding the
+// test records in VBLK slots of recordSize bytes. Records that don't fit
+// in a slot are split into fragments, which are stored in reverse order.
+func testDatabase(recordSize int) []byte {
+	data := make([]byte, testDatabaseSectors*512)
+	copy(data[1*512:], mustDecode(testTOCBlock))
+
+	vmdb := data[4*512:]
+	copy(vmdb, mustDecode(testVMDB))
+	binary.BigEndian.PutUint32(vmdb[0x08:0x0C], uint32(recordSize))
+
+	records := []string{
+		testDiskGroupRecord,
+		testDiskRecord3,
+		testDiskRecord4,
+		testVolumeRecord,
+		testComponentRecord,
+		testPartitionRecord,
+	}
+	slot := 0x200 / recordSize
+	for i, record := range records {
+		record := mustDecode(record)
+		chunk := recordSize - vblkHeaderLength
+		count := (len(record) + chunk - 1) / chunk
+		for index := count - 1; index >= 0; index-- {
+			b := vmdb[slot*recordSize : (slot+1)*recordSize]
+			copy(b, mustDecode(testFragmentHeader))
+			binary.BigEndian.PutUint32(b[0x04:0x08], uint32(slot))
+			binary.BigEndian.PutUint32(b[0x08:0x0C], uint32(i+1))
+			binary.BigEndian.PutUint16(b[0x0C:0x0E], uint16(index))
+			binary.BigEndian.PutUint16(b[0x0E:0x10], uint16(count))
+			end := (index + 1) * chunk
+			if end > len(record) {
+				end = len(record)
+			}
+			copy(b[vblkHeaderLength:], record[index*chunk:end])
+			slot++
+		}
+	}
+	binary.BigEndian.PutUint32(vmdb[0x04:0x08], uint32(slot))
+	return data
+}
+
+func TestReadDatabase(t *testing.T) {
+	tests := []struct {
+		name       string
+		recordSize int
+		modify     func(data []byte)
+		err        error
+	}{
+		{"Slots128", 0x80, nil, nil},
+		{"Slots32", 0x20, nil, nil},
+		{"BackupTOC", 0x80, func(data []byte) {
+			copy(data[(testDatabaseSectors-2)*512:], data[1*512:2*512])
+			copy(data[1*512:], make([]byte, 512))
+		}, nil},
+		{"NoTOC", 0x80, func(data []byte) {
+			copy(data[1*512:], make([]byte, 512))
+		}, ErrInvalidTOCBlock},
+		{"ConfigBeyondEnd", 0x80, func(data []byte) {
+			binary.BigEndian.PutUint64(data[1*512+0x36:], testDatabaseSectors)
+		}, ErrInvalidTOCBlock},
+		{"NoVMDB", 0x80, func(data []byte) {
+			copy(data[4*512:], "VMDC")
+		}, ErrInvalidVMDB},
+		{"MissingFragment", 0x20, func(data []byte) {
+			copy(data[4*512+0x200:], make([]byte, 0x20))
+		}, ErrInvalidVBLK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := testDatabase(test.recordSize)
+			if test.modify != nil {
+				test.modify(data)
+			}
+			db, toc, err := readDatabase(bytes.NewReader(data), 0, testDatabaseSectors, 512)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+			if err != nil {
+				return
+			}
+			if config, ok := toc.Region(ConfigRegion); !ok || config.Start != 4 {
+				t.Errorf("got config region %+v, want start 4", config)
+			}
+			if db.VMDB.RecordSize != uint32(test.recordSize) {
+				t.Errorf("got record size %d, want %d", db.VMDB.RecordSize, test.recordSize)
+			}
+			want := Database{
+				VMDB:       db.VMDB,
+				DiskGroup:  testDiskGroup,
+				Disks:      []Disk{testDisk3, testDisk4},
+				Volumes:    []Volume{testVolume},
+				Components: []Component{testComponent},
+				Partitions: []Partition{testPartition},
+			}
+			if !reflect.DeepEqual(*db, want) {
+				t.Errorf("got %+v, want %+v", *db, want)
+			}
+		})
+	}
+}
+
+// testDisk returns a reader for 256 bytes holding the values 0 to 255.
+func testDisk() *bytes.Reader {
+	data := make([]byte, 256)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	return bytes.NewReader(data)
+}
+
+func TestConcatReader(t *testing.T) {
+	disk := testDisk()
+	extents := []extent{
+		{Offset: 0, Length: 4, Base: 10, r: disk},
+		{Offset: 4, Length: 6, Base: 100, r: disk},
+		{Offset: 10, Length: 2, Base: 50, r: disk},
+	}
+	tests := []struct {
+		name    string
+		extents []extent
+		off     int64
+		length  int
+		want    []byte
+		err     error
+	}{
+		{"Within", extents, 1, 2, []byte{11, 12}, nil},
+		{"AcrossExtents", extents, 2, 4, []byte{12, 13, 100, 101}, nil},
+		{"All", extents, 0, 12, []byte{10, 11, 12, 13, 100, 101, 102, 103, 104, 105, 50, 51}, nil},
+		{"PastEnd", extents, 9, 4, []byte{105, 50, 51}, io.EOF},
+		{"AtEnd", extents, 12, 1, []byte{}, io.EOF},
+		{"Negative", extents, -1, 1, []byte{}, ErrNegativeOffset},
+		{"ShortDisk", []extent{{Offset: 0, Length: 4, Base: 254, r: disk}}, 0, 4, []byte{254, 255}, io.ErrUnexpectedEOF},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := newConcatReader(test.extents)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := make([]byte, test.length)
+			n, err := r.ReadAt(p, test.off)
+			if !errors.Is(err, test.err) {
+				t.Errorf("got %v, want %v", err, test.err)
+			}
+			if !bytes.Equal(p[:n], test.want) {
+				t.Errorf("got %v, want %v", p[:n], test.want)
+			}
+		})
+	}
+}
+
+func TestConcatReaderInvalid(t *testing.T) {
+	disk := testDisk()
+	tests := []struct {
+		name    string
+		extents []extent
+	}{
+		{"Offset", []extent{{Offset: 4, Length: 4, r: disk}}},
+		{"Gap", []extent{{Offset: 0, Length: 4, r: disk}, {Offset: 6, Length: 4, r: disk}}},
+		{"Overlap", []extent{{Offset: 0, Length: 4, r: disk}, {Offset: 2, Length: 4, r: disk}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := newConcatReader(test.extents); !errors.Is(err, ErrInvalidVolume) {
+				t.Errorf("got %v, want %v", err, ErrInvalidVolume)
+			}
+		})
+	}
+}
+
+func TestStripeReader(t *testing.T) {
+	// Two columns in 4-byte stripes. The first column is 12 bytes long
+	// and split across two extents of one disk, the second is 10 bytes
+	// long, so only 8 bytes of each column are used.
+	const stripe = 4
+	volume := make([]byte, 16)
+	disk1, disk2 := bytes.Repeat([]byte{0xFF}, 80), bytes.Repeat([]byte{0xFF}, 16)
+	for pos := range volume {
+		volume[pos] = byte(pos + 1)
+		s := pos / stripe
+		off := s/2*stripe + pos%stripe
+		switch {
+		case s%2 == 1:
+			disk2[off] = volume[pos]
+		case off < 6:
+			disk1[20+off] = volume[pos]
+		default:
+			disk1[60+off-6] = volume[pos]
+		}
+	}
+	columns := [][]extent{
+		{
+			{Offset: 6, Length: 6, Base: 60, r: bytes.NewReader(disk1)},
+			{Offset: 0, Length: 6, Base: 20, r: bytes.NewReader(disk1)},
+		},
+		{
+			{Offset: 0, Length: 10, Base: 0, r: bytes.NewReader(disk2)},
+		},
+	}
+	r, err := newStripeReader(columns, stripe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.size != int64(len(volume)) {
+		t.Fatalf("got size %d, want %d", r.size, len(volume))
+	}
+
+	tests := []struct {
+		name   string
+		off    int64
+		length int
+		err    error
+	}{
+		{"Stripe", 4, 4, nil},
+		{"AcrossColumns", 2, 4, nil},
+		{"AcrossStripes", 3, 10, nil},
+		{"AcrossExtents", 8, 4, nil},
+		{"All", 0, 16, nil},
+		{"PastEnd", 14, 4, io.EOF},
+		{"AtEnd", 16, 1, io.EOF},
+		{"Negative", -1, 1, ErrNegativeOffset},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := make([]byte, test.length)
+			n, err := r.ReadAt(p, test.off)
+			if !errors.Is(err, test.err) {
+				t.Errorf("got %v, want %v", err, test.err)
+			}
+			var want []byte
+			if test.off >= 0 && test.off < int64(len(volume)) {
+				want = volume[test.off:]
+				if len(want) > test.length {
+					want = want[:test.length]
+				}
+			}
+			if !bytes.Equal(p[:n], want) {
+				t.Errorf("got %v, want %v", p[:n], want)
+			}
+		})
+	}
+}
+
+func TestOpenVolume(t *testing.T) {
+	// A striped volume of four sectors with one sector stripes. Sector s
+	// of the volume is filled with s+1 and stored in column s%2.
+	const sectorSize = 512
+	disk1, disk2 := make([]byte, 6*sectorSize), make([]byte, 6*sectorSize)
+	for s := 0; s < 4; s++ {
+		disk, start := disk1, 1+2
+		if s%2 == 1 {
+			disk, start = disk2, 1+1
+		}
+		copy(disk[(start+s/2)*sectorSize:], bytes.Repeat([]byte{byte(s + 1)}, sectorSize))
+	}
+
+	tests := []struct {
+		name       string
+		columns    uint64
+		stripeSize uint64
+		missing    bool
+		err        error
+	}{
+		{"Striped", 2, 1, false, nil},
+		{"MissingDisk", 2, 1, true, ErrMissingDisk},
+		{"NoColumns", 0, 1, false, ErrInvalidVolume},
+		{"TooManyColumns", 3, 1, false, ErrInvalidVolume},
+		{"NoStripeSize", 2, 0, false, ErrInvalidVolume},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := &Database{
+				Disks:      []Disk{testDisk3, testDisk4},
+				Volumes:    []Volume{{ID: 4, Name: "Volume1", Components: 1, Size: 4}},
+				Components: []Component{{ID: 5, Name: "Volume1-01", Type: StripedComponent, Partitions: 2, VolumeID: 4, StripeSize: test.stripeSize, Columns: test.columns}},
+				Partitions: []Partition{
+					{ID: 6, Name: "Disk1-01", Start: 2, Size: 2, ComponentID: 5, DiskID: 2, Index: 0},
+					{ID: 7, Name: "Disk2-01", Start: 1, Size: 2, ComponentID: 5, DiskID: 3, Index: 1},
+				},
+			}
+			members := []*Member{{
+				Header:     PrivateHeader{DiskGUID: testDiskGUID, DiskGroupGUID: testDiskGroupGUID, LogicalDiskStart: 1},
+				Database:   db,
+				SectorSize: sectorSize,
+				r:          bytes.NewReader(disk1),
+			}}
+			if !test.missing {
+				members = append(members, &Member{
+					Header:     PrivateHeader{DiskGUID: testDisk2GUID, DiskGroupGUID: testDiskGroupGUID, LogicalDiskStart: 1},
+					Database:   db,
+					SectorSize: sectorSize,
+					r:          bytes.NewReader(disk2),
+				})
+			}
+			g, err := NewGroup(members...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, err := g.OpenVolume(db.Volumes[0])
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+			if err != nil {
+				return
+			}
+			data, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(data) != 4*sectorSize {
+				t.Fatalf("got %d bytes, want %d", len(data), 4*sectorSize)
+			}
+			for s := 0; s < 4; s++ {
+				if b := data[s*sectorSize]; b != byte(s+1) {
+					t.Errorf("sector %d: got %d, want %d", s, b, s+1)
+				}
+			}
+		})
+	}
+}
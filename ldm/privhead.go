@@ -0,0 +1,118 @@
+package ldm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// https://github.com/torvalds/linux/blob/master/block/partitions/ldm.c
+// https://github.com/mdbooth/libldm/blob/master/src/ldm.c
+
+// PrivateHeaderLength is the length of the portion of an LDM private
+// header that is parsed, in bytes. The header occupies a full sector.
+const PrivateHeaderLength = 0x167
+
+// PrivateHeaderSector is the sector that holds the primary private header
+// on MBR disks. On GPT disks the private header is held in the last sector
+// of the LDM metadata partition.
+const PrivateHeaderSector = 6
+
+// PrivateHeaderSignature is the signature of LDM private headers.
+var PrivateHeaderSignature = [8]byte{'P', 'R', 'I', 'V', 'H', 'E', 'A', 'D'}
+
+// PrivateHeader is the LDM private header of a dynamic disk. It identifies
+// the disk and its disk group and locates the LDM database. Values are
+// stored in big-endian byte order.
+//
+// Locations and sizes are measured in sectors.
+type PrivateHeader struct {
+	Signature        [8]byte   // 0x000:0x008
+	Sequence         uint32    // 0x008:0x00C
+	VersionMajor     uint16    // 0x00C:0x00E
+	VersionMinor     uint16    // 0x00E:0x010
+	Timestamp        uint64    // 0x010:0x018
+	DiskGUID         uuid.UUID // 0x030:0x070 ASCII
+	HostGUID         uuid.UUID // 0x070:0x0B0 ASCII
+	DiskGroupGUID    uuid.UUID // 0x0B0:0x0F0 ASCII
+	DiskGroupName    string    // 0x0F0:0x110
+	LogicalDiskStart uint64    // 0x11B:0x123 Start of the space available to partitions
+	LogicalDiskSize  uint64    // 0x123:0x12B
+	ConfigStart      uint64    // 0x12B:0x133 Start of the LDM database
+	ConfigSize       uint64    // 0x133:0x13B
+	TOCCount         uint64    // 0x13B:0x143
+	TOCSize          uint64    // 0x143:0x14B
+	ConfigCount      uint32    // 0x14B:0x14F
+	LogCount         uint32    // 0x14F:0x153
+	ConfigLength     uint64    // 0x153:0x15B
+	LogLength        uint64    // 0x15B:0x163
+	DiskSignature    uint32    // 0x163:0x167 MBR disk signature
+}
+
+// UnmarshalBinary unmarshals the big-endian binary representation of an
+// LDM private header into h.
+//
+// The provided data must be at least 0x167 bytes long.
+func (h *PrivateHeader) UnmarshalBinary(data []byte) (err error) {
+	if len(data) < PrivateHeaderLength {
+		return ErrTruncatedData
+	}
+	copy(h.Signature[:], data[0:8])
+	if h.Signature != PrivateHeaderSignature {
+		return ErrInvalidPrivateHeader
+	}
+	h.Sequence = binary.BigEndian.Uint32(data[0x08:0x0C])
+	h.VersionMajor = binary.BigEndian.Uint16(data[0x0C:0x0E])
+	h.VersionMinor = binary.BigEndian.Uint16(data[0x0E:0x10])
+	h.Timestamp = binary.BigEndian.Uint64(data[0x10:0x18])
+	if h.DiskGUID, err = parseGUIDString(data[0x30:0x70]); err != nil {
+		return fmt.Errorf("%w: disk GUID: %v", ErrInvalidPrivateHeader, err)
+	}
+	h.HostGUID, _ = parseGUIDString(data[0x70:0xB0])
+	if h.DiskGroupGUID, err = parseGUIDString(data[0xB0:0xF0]); err != nil {
+		return fmt.Errorf("%w: disk group GUID: %v", ErrInvalidPrivateHeader, err)
+	}
+	h.DiskGroupName = cString(data[0xF0:0x110])
+	h.LogicalDiskStart = binary.BigEndian.Uint64(data[0x11B:0x123])
+	h.LogicalDiskSize = binary.BigEndian.Uint64(data[0x123:0x12B])
+	h.ConfigStart = binary.BigEndian.Uint64(data[0x12B:0x133])
+	h.ConfigSize = binary.BigEndian.Uint64(data[0x133:0x13B])
+	h.TOCCount = binary.BigEndian.Uint64(data[0x13B:0x143])
+	h.TOCSize = binary.BigEndian.Uint64(data[0x143:0x14B])
+	h.ConfigCount = binary.BigEndian.Uint32(data[0x14B:0x14F])
+	h.LogCount = binary.BigEndian.Uint32(data[0x14F:0x153])
+	h.ConfigLength = binary.BigEndian.Uint64(data[0x153:0x15B])
+	h.LogLength = binary.BigEndian.Uint64(data[0x15B:0x163])
+	h.DiskSignature = binary.BigEndian.Uint32(data[0x163:0x167])
+	return nil
+}
+
+// Validate returns a non-nil error if the private header has an
+// unsupported version or does not locate an LDM database.
+//
+// Version 2.11 is used by Windows 2000 and XP, and version 2.12 by Windows
+// Vista and later.
+func (h *PrivateHeader) Validate() error {
+	if h.VersionMajor != 2 || (h.VersionMinor != 11 && h.VersionMinor != 12) {
+		return fmt.Errorf("%w: private header version %d.%d", ErrUnsupportedVersion, h.VersionMajor, h.VersionMinor)
+	}
+	if h.ConfigSize == 0 || h.LogicalDiskSize == 0 {
+		return ErrInvalidPrivateHeader
+	}
+	return nil
+}
+
+// parseGUIDString parses a null-terminated ASCII GUID.
+func parseGUIDString(data []byte) (uuid.UUID, error) {
+	return uuid.Parse(cString(data))
+}
+
+// cString returns the contents of data up to its first null byte.
+func cString(data []byte) string {
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		data = data[:i]
+	}
+	return string(data)
+}
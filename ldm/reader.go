@@ -0,0 +1,124 @@
+package ldm
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// extent maps a range of a component or column to a range of a disk.
+// Offsets and lengths are measured in bytes.
+type extent struct {
+	Offset int64 // Offset within the component or column
+	Length int64
+	Base   int64 // Offset on the disk
+	r      io.ReaderAt
+}
+
+// concatReader reads extents joined end to end.
+type concatReader struct {
+	extents []extent
+	size    int64
+}
+
+// newConcatReader returns a reader for extents, which must be sorted by
+// offset and leave no gaps.
+func newConcatReader(extents []extent) (*concatReader, error) {
+	var size int64
+	for _, e := range extents {
+		if e.Offset != size {
+			return nil, fmt.Errorf("%w: extent at offset %d does not follow the previous extent ending at %d", ErrInvalidVolume, e.Offset, size)
+		}
+		size += e.Length
+	}
+	return &concatReader{extents: extents, size: size}, nil
+}
+
+// ReadAt reads len(p) bytes starting at off.
+func (c *concatReader) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, ErrNegativeOffset
+	}
+	for n < len(p) {
+		pos := off + int64(n)
+		if pos >= c.size {
+			return n, io.EOF
+		}
+		i := sort.Search(len(c.extents), func(i int) bool {
+			return c.extents[i].Offset+c.extents[i].Length > pos
+		})
+		e := c.extents[i]
+		want := int64(len(p) - n)
+		if remaining := e.Offset + e.Length - pos; want > remaining {
+			want = remaining
+		}
+		m, err := e.r.ReadAt(p[n:n+int(want)], e.Base+pos-e.Offset)
+		n += m
+		if m < int(want) {
+			if err == nil || err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// stripeReader reads columns interleaved in fixed size stripes.
+type stripeReader struct {
+	columns []*concatReader
+	stripe  int64
+	size    int64
+}
+
+// newStripeReader returns a reader for columns interleaved in stripes of
+// the given size. The usable size is limited by the smallest column.
+func newStripeReader(columns [][]extent, stripe int64) (*stripeReader, error) {
+	s := &stripeReader{stripe: stripe}
+	var smallest int64 = -1
+	for i, extents := range columns {
+		if len(extents) == 0 {
+			return nil, fmt.Errorf("%w: column %d has no partitions", ErrInvalidVolume, i)
+		}
+		sort.SliceStable(extents, func(i, j int) bool { return extents[i].Offset < extents[j].Offset })
+		column, err := newConcatReader(extents)
+		if err != nil {
+			return nil, err
+		}
+		if smallest < 0 || column.size < smallest {
+			smallest = column.size
+		}
+		s.columns = append(s.columns, column)
+	}
+	s.size = smallest / stripe * stripe * int64(len(columns))
+	return s, nil
+}
+
+// ReadAt reads len(p) bytes starting at off.
+func (s *stripeReader) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, ErrNegativeOffset
+	}
+	columns := int64(len(s.columns))
+	for n < len(p) {
+		pos := off + int64(n)
+		if pos >= s.size {
+			return n, io.EOF
+		}
+		stripe, within := pos/s.stripe, pos%s.stripe
+		column := s.columns[stripe%columns]
+		want := int64(len(p) - n)
+		if remaining := s.stripe - within; want > remaining {
+			want = remaining
+		}
+		m, err := column.ReadAt(p[n:n+int(want)], stripe/columns*s.stripe+within)
+		n += m
+		if m < int(want) {
+			if err == nil || err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return n, err
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,71 @@
+package ldm
+
+import (
+	"encoding/binary"
+)
+
+// TOCBlockLength is the length of the portion of an LDM table of contents
+// block that is parsed, in bytes.
+const TOCBlockLength = 0x68
+
+// TOCBlockSignature is the signature of LDM table of contents blocks.
+var TOCBlockSignature = [8]byte{'T', 'O', 'C', 'B', 'L', 'O', 'C', 'K'}
+
+// Names of the regions listed in a table of contents block.
+const (
+	ConfigRegion = "config" // Holds the VMDB and its VBLK records
+	LogRegion    = "log"    // Holds the transaction log
+)
+
+// TOCRegion is a region of the LDM database listed in a table of contents
+// block. Its location and size are measured in sectors from the start of
+// the database.
+type TOCRegion struct {
+	Name  string
+	Flags uint16
+	Start uint64
+	Size  uint64
+}
+
+// TOCBlock is the table of contents of an LDM database. Values are stored
+// in big-endian byte order.
+type TOCBlock struct {
+	Signature [8]byte // 0x00:0x08
+	Sequence  uint32  // 0x08:0x0C
+	Regions   [2]TOCRegion
+}
+
+// UnmarshalBinary unmarshals the big-endian binary representation of an
+// LDM table of contents block into toc.
+//
+// The provided data must be at least 0x68 bytes long.
+func (toc *TOCBlock) UnmarshalBinary(data []byte) error {
+	if len(data) < TOCBlockLength {
+		return ErrTruncatedData
+	}
+	copy(toc.Signature[:], data[0:8])
+	if toc.Signature != TOCBlockSignature {
+		return ErrInvalidTOCBlock
+	}
+	toc.Sequence = binary.BigEndian.Uint32(data[0x08:0x0C])
+	for i := range toc.Regions {
+		b := data[0x24+i*0x22:]
+		toc.Regions[i] = TOCRegion{
+			Name:  cString(b[0x00:0x08]),
+			Flags: binary.BigEndian.Uint16(b[0x08:0x0A]),
+			Start: binary.BigEndian.Uint64(b[0x0A:0x12]),
+			Size:  binary.BigEndian.Uint64(b[0x12:0x1A]),
+		}
+	}
+	return nil
+}
+
+// Region returns the region with the given name.
+func (toc *TOCBlock) Region(name string) (region TOCRegion, ok bool) {
+	for _, region := range toc.Regions {
+		if region.Name == name {
+			return region, true
+		}
+	}
+	return TOCRegion{}, false
+}
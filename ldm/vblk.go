@@ -0,0 +1,308 @@
+package ldm
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gentlemanautomaton/ntfs/mspart"
+	"github.com/google/uuid"
+)
+
+// vblkHeaderLength is the length of the header that precedes each VBLK
+// fragment.
+const vblkHeaderLength = 0x10
+
+// vblkDataOffset is the offset of the record-specific data within an
+// assembled VBLK.
+const vblkDataOffset = 0x18
+
+// VBLKSignature is the signature of LDM database records.
+var VBLKSignature = [4]byte{'V', 'B', 'L', 'K'}
+
+// RecordType identifies the type of a VBLK record. The low four bits hold
+// the kind of object and the high four bits its revision.
+type RecordType byte
+
+// VBLK record types.
+const (
+	VolumeRecord     RecordType = 0x51
+	ComponentRecord  RecordType = 0x32
+	PartitionRecord  RecordType = 0x33
+	DiskRecord3      RecordType = 0x34 // Disk with an ASCII GUID
+	DiskRecord4      RecordType = 0x44 // Disk with a binary GUID
+	DiskGroupRecord3 RecordType = 0x35
+	DiskGroupRecord4 RecordType = 0x45
+)
+
+// VBLK record flags.
+const (
+	flagComponentStripe = 0x10
+	flagPartitionIndex  = 0x08
+	flagVolumeID1       = 0x08
+	flagVolumeID2       = 0x20
+	flagVolumeSize      = 0x80
+	flagVolumeDrive     = 0x02
+)
+
+// ComponentType identifies how the partitions of a component are
+// combined.
+type ComponentType byte
+
+// Component types.
+const (
+	StripedComponent ComponentType = 1
+	SpannedComponent ComponentType = 2 // Concatenated; also used for simple volumes
+	RAIDComponent    ComponentType = 3 // Striped with parity
+)
+
+// String returns a string representation of the component type.
+func (t ComponentType) String() string {
+	switch t {
+	case StripedComponent:
+		return "Striped"
+	case SpannedComponent:
+		return "Spanned"
+	case RAIDComponent:
+		return "RAID-5"
+	default:
+		return fmt.Sprintf("ComponentType %d", byte(t))
+	}
+}
+
+// Volume is a volume record in an LDM database.
+type Volume struct {
+	ID            uint64
+	Name          string // e.g. "Volume1"
+	Type          string // "gen" or "raid5"
+	State         string // e.g. "ACTIVE"
+	Components    uint64 // Number of components
+	Size          uint64 // In sectors
+	PartitionType mspart.MBR
+	GUID          uuid.UUID
+	DriveHint     string // e.g. "E:"
+}
+
+// Component is a component record in an LDM database. Components join the
+// partitions that hold the data of a volume. A mirrored volume has one
+// component per copy.
+type Component struct {
+	ID         uint64
+	Name       string
+	State      string
+	Type       ComponentType
+	Partitions uint64 // Number of partitions
+	VolumeID   uint64
+	StripeSize uint64 // In sectors; striped and RAID components only
+	Columns    uint64 // Striped and RAID components only
+}
+
+// Partition is a partition record in an LDM database. It describes a range
+// of sectors on a disk that holds part of a component.
+type Partition struct {
+	ID           uint64
+	Name         string
+	Start        uint64 // In sectors from the logical disk start of the disk
+	VolumeOffset uint64 // In sectors from the start of its component or column
+	Size         uint64 // In sectors
+	ComponentID  uint64
+	DiskID       uint64
+	Index        uint64 // Column index within a striped component
+}
+
+// Disk is a disk record in an LDM database.
+type Disk struct {
+	ID   uint64
+	Name string // e.g. "Disk1"
+	GUID uuid.UUID
+}
+
+// DiskGroup is a disk group record in an LDM database.
+type DiskGroup struct {
+	ID   uint64
+	Name string
+}
+
+// vblkFragment is a single VBLK slot in the database. Records that don't
+// fit in one slot are split across several fragments.
+type vblkFragment struct {
+	Sequence uint32
+	Group    uint32
+	Index    uint16
+	Count    uint16
+	Data     []byte // Data following the fragment header
+}
+
+func (f *vblkFragment) UnmarshalBinary(data []byte) error {
+	if len(data) < vblkHeaderLength {
+		return ErrTruncatedData
+	}
+	if [4]byte{data[0], data[1], data[2], data[3]} != VBLKSignature {
+		return ErrInvalidVBLK
+	}
+	f.Sequence = binary.BigEndian.Uint32(data[0x04:0x08])
+	f.Group = binary.BigEndian.Uint32(data[0x08:0x0C])
+	f.Index = binary.BigEndian.Uint16(data[0x0C:0x0E])
+	f.Count = binary.BigEndian.Uint16(data[0x0E:0x10])
+	f.Data = data[vblkHeaderLength:]
+	return nil
+}
+
+// vblkParser reads the variable length fields of a VBLK record.
+//
+// Numbers are stored as a length byte followed by that many big-endian
+// bytes. Strings are stored as a length byte followed by that many bytes.
+type vblkParser struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (p *vblkParser) bytes(n int) []byte {
+	if p.err != nil {
+		return nil
+	}
+	if n < 0 || p.pos+n > len(p.data) {
+		p.err = fmt.Errorf("%w: field at offset %#x exceeds record length", ErrInvalidVBLK, p.pos)
+		return nil
+	}
+	b := p.data[p.pos : p.pos+n]
+	p.pos += n
+	return b
+}
+
+func (p *vblkParser) skip(n int) {
+	p.bytes(n)
+}
+
+func (p *vblkParser) byte() byte {
+	if b := p.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (p *vblkParser) uint64() uint64 {
+	if b := p.bytes(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (p *vblkParser) number() uint64 {
+	n := int(p.byte())
+	if n > 8 && p.err == nil {
+		p.err = fmt.Errorf("%w: number at offset %#x has length %d", ErrInvalidVBLK, p.pos-1, n)
+	}
+	var v uint64
+	for _, b := range p.bytes(n) {
+		v = v<<8 | uint64(b)
+	}
+	return v
+}
+
+func (p *vblkParser) string() string {
+	return string(p.bytes(int(p.byte())))
+}
+
+func (p *vblkParser) guid() uuid.UUID {
+	var g uuid.UUID
+	copy(g[:], p.bytes(16))
+	return g
+}
+
+// parseRecord parses an assembled VBLK record, including its first
+// fragment header, and adds it to db. Records of unknown types are ignored.
+func (db *Database) parseRecord(data []byte) error {
+	if len(data) < vblkDataOffset {
+		return ErrTruncatedData
+	}
+	flags := data[0x12]
+	typ := RecordType(data[0x13])
+	p := vblkParser{data: data, pos: vblkDataOffset}
+	id := p.number()
+	name := p.string()
+
+	switch typ {
+	case VolumeRecord:
+		v := Volume{ID: id, Name: name}
+		v.Type = p.string()
+		p.string() // Unknown, typically empty
+		v.State = cString(p.bytes(14))
+		p.skip(7) // Type, volume number, flags and zeroes
+		v.Components = p.number()
+		p.skip(16) // Log commit ID and unknown
+		v.Size = p.number()
+		p.skip(4)
+		v.PartitionType = mspart.MBR(p.byte())
+		v.GUID = p.guid()
+		if flags&flagVolumeID1 != 0 {
+			p.string()
+		}
+		if flags&flagVolumeID2 != 0 {
+			p.string()
+		}
+		if flags&flagVolumeSize != 0 {
+			p.number()
+		}
+		if flags&flagVolumeDrive != 0 {
+			v.DriveHint = p.string()
+		}
+		if p.err == nil {
+			db.Volumes = append(db.Volumes, v)
+		}
+	case ComponentRecord:
+		c := Component{ID: id, Name: name}
+		c.State = p.string()
+		c.Type = ComponentType(p.byte())
+		p.skip(4)
+		c.Partitions = p.number()
+		p.skip(16) // Log commit ID and unknown
+		c.VolumeID = p.number()
+		p.skip(1)
+		if flags&flagComponentStripe != 0 {
+			c.StripeSize = p.number()
+			c.Columns = p.number()
+		}
+		if p.err == nil {
+			db.Components = append(db.Components, c)
+		}
+	case PartitionRecord:
+		part := Partition{ID: id, Name: name}
+		p.skip(12) // Zeroes and log commit ID
+		part.Start = p.uint64()
+		part.VolumeOffset = p.uint64()
+		part.Size = p.number()
+		part.ComponentID = p.number()
+		part.DiskID = p.number()
+		if flags&flagPartitionIndex != 0 {
+			part.Index = p.number()
+		}
+		if p.err == nil {
+			db.Partitions = append(db.Partitions, part)
+		}
+	case DiskRecord3:
+		d := Disk{ID: id, Name: name}
+		var err error
+		if d.GUID, err = uuid.Parse(p.string()); err != nil && p.err == nil {
+			p.err = fmt.Errorf("%w: disk GUID: %v", ErrInvalidVBLK, err)
+		}
+		if p.err == nil {
+			db.Disks = append(db.Disks, d)
+		}
+	case DiskRecord4:
+		d := Disk{ID: id, Name: name}
+		d.GUID = p.guid()
+		if p.err == nil {
+			db.Disks = append(db.Disks, d)
+		}
+	case DiskGroupRecord3, DiskGroupRecord4:
+		if p.err == nil {
+			db.DiskGroup = DiskGroup{ID: id, Name: name}
+		}
+	}
+
+	if p.err != nil {
+		return fmt.Errorf("record %d (type %#x): %w", id, byte(typ), p.err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package ldm
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// VMDBLength is the length of the portion of an LDM volume manager
+// database header that is parsed, in bytes.
+const VMDBLength = 0x85
+
+// VMDBSignature is the signature of LDM volume manager database headers.
+var VMDBSignature = [4]byte{'V', 'M', 'D', 'B'}
+
+// VMDB is the header of the volume manager database, which precedes the
+// VBLK records that describe the disk group. Values are stored in
+// big-endian byte order.
+type VMDB struct {
+	Signature         [4]byte   // 0x00:0x04
+	LastSequence      uint32    // 0x04:0x08 Sequence number of the last VBLK
+	RecordSize        uint32    // 0x08:0x0C Size of each VBLK in bytes
+	FirstRecordOffset uint32    // 0x0C:0x10 Offset of the first VBLK in bytes
+	UpdateStatus      uint16    // 0x10:0x12
+	VersionMajor      uint16    // 0x12:0x14
+	VersionMinor      uint16    // 0x14:0x16
+	DiskGroupName     string    // 0x16:0x35
+	DiskGroupGUID     uuid.UUID // 0x35:0x75 ASCII
+	CommittedSequence uint64    // 0x75:0x7D
+	PendingSequence   uint64    // 0x7D:0x85
+}
+
+// UnmarshalBinary unmarshals the big-endian binary representation of an
+// LDM volume manager database header into vmdb.
+//
+// The provided data must be at least 0x85 bytes long.
+func (vmdb *VMDB) UnmarshalBinary(data []byte) error {
+	if len(data) < VMDBLength {
+		return ErrTruncatedData
+	}
+	copy(vmdb.Signature[:], data[0:4])
+	if vmdb.Signature != VMDBSignature {
+		return ErrInvalidVMDB
+	}
+	vmdb.LastSequence = binary.BigEndian.Uint32(data[0x04:0x08])
+	vmdb.RecordSize = binary.BigEndian.Uint32(data[0x08:0x0C])
+	vmdb.FirstRecordOffset = binary.BigEndian.Uint32(data[0x0C:0x10])
+	vmdb.UpdateStatus = binary.BigEndian.Uint16(data[0x10:0x12])
+	vmdb.VersionMajor = binary.BigEndian.Uint16(data[0x12:0x14])
+	vmdb.VersionMinor = binary.BigEndian.Uint16(data[0x14:0x16])
+	vmdb.DiskGroupName = cString(data[0x16:0x35])
+	vmdb.DiskGroupGUID, _ = parseGUIDString(data[0x35:0x75])
+	vmdb.CommittedSequence = binary.BigEndian.Uint64(data[0x75:0x7D])
+	vmdb.PendingSequence = binary.BigEndian.Uint64(data[0x7D:0x85])
+	if vmdb.VersionMajor != 4 || vmdb.VersionMinor != 10 {
+		return fmt.Errorf("%w: VMDB version %d.%d", ErrUnsupportedVersion, vmdb.VersionMajor, vmdb.VersionMinor)
+	}
+	if vmdb.RecordSize <= vblkHeaderLength || vmdb.FirstRecordOffset == 0 {
+		return ErrInvalidVMDB
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package ntfs
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+type binaryCodec interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// The test data was captured from a volume formatted by Windows.
+const (
+	testBootRecord          = "eb52904e5446532020202000020200000000000000f800003f00ff00000800000000000080008000ff4f000000000000550d000000000000080000000000000001000000040000004d9ca796bda7965600000000"
+	testFileRecordHeader    = "46494c4530000300e0bb1000000000000100010038000100d0010000000400000000000000000000060000002e000000"
+	testNonresidentData     = "800000004800000001004000000006000000000000000000ff000000000000004000000000000000000004000000000000000400000000000000040000000000"
+	testCompressedData      = "8000000000020000010000000100000000000000000000006f05000000000000480004000000000000002d000000000000002d000000000000002d000000000000d0020000000000"
+	testStandardInformation = "cfe269f6db53d401cf937b1ddc53d401cf937b1ddc53d401cf937b1ddc53d40120080000000000000000000000000000000000000c01000000000000000000000000000000000000"
+	testFileName            = "2d00000000000100cfe269f6db53d401cfe269f6db53d401cfe269f6db53d401cfe269f6db53d4010000000000000000000000000000000020080000000000001d00480065006c006c006f00200077006f0072006c00640020007400650078007400200064006f00630075006d0065006e0074002e00740078007400"
+	testObjectID            = "ccc68de952b5e81189496807157ff151"
+	testVolumeInformation   = "000000000000000003018000"
+)
+
+func TestMarshalRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		value binaryCodec
+		data  string
+	}{
+		{"BootRecord", &BootRecord{}, testBootRecord},
+		{"ParameterBlock", &ParameterBlock{}, testBootRecord[22:]},
+		{"FileRecordSegmentHeader", &FileRecordSegmentHeader{}, testFileRecordHeader},
+		{"AttributeRecordHeader", &AttributeRecordHeader{}, testNonresidentData[:32]},
+		{"NonresidentAttributeRecordHeader", &NonresidentAttributeRecordHeader{}, testNonresidentData[32:]},
+		{"CompressedAttributeRecordHeader", &AttributeRecordHeader{}, testCompressedData[:32]},
+		{"CompressedNonresidentAttributeRecordHeader", &NonresidentAttributeRecordHeader{}, testCompressedData[32:]},
+		{"StandardInformation", &StandardInformation{}, testStandardInformation},
+		{"StandardInformationShort", &StandardInformation{}, testStandardInformation[:96]},
+		{"StandardInformationZeroTime", &StandardInformation{}, testStandardInformation[:48] + "0000000000000000" + testStandardInformation[64:]},
+		{"FileName", &FileName{}, testFileName},
+		{"FileNameUnpairedSurrogate", &FileName{}, testFileName[:132] + "00d8" + testFileName[136:]},
+		{"FileNameTrailingSurrogate", &FileName{}, testFileName[:len(testFileName)-4] + "3dd8"},
+		{"ObjectID", &ObjectID{}, testObjectID},
+		{"VolumeInformation", &VolumeInformation{}, testVolumeInformation},
+		{"SegmentReference", &SegmentReference{}, testFileName[:16]},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := hex.DecodeString(test.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := test.value.UnmarshalBinary(data); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			out, err := test.value.MarshalBinary()
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if !bytes.Equal(out, data) {
+				t.Errorf("marshal:\n got %x\nwant %x", out, data)
+			}
+		})
+	}
+}
+
+func TestCompressedLength(t *testing.T) {
+	data, _ := hex.DecodeString(testCompressedData[32:])
+	var header NonresidentAttributeRecordHeader
+	if err := header.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if header.CompressedLength != 184320 {
+		t.Errorf("compressed length: got %d, want 184320", header.CompressedLength)
+	}
+	if err := header.UnmarshalBinary(data[:NonresidentAttributeRecordHeaderLength]); err != ErrTruncatedData {
+		t.Errorf("truncated compressed header: got %v, want %v", err, ErrTruncatedData)
+	}
+}
+
+func TestZeroFileTime(t *testing.T) {
+	info := StandardInformation{FileModification: time.Date(2018, 8, 7, 6, 5, 4, 300, time.UTC)}
+	data, err := info.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data[0:8], make([]byte, 8)) {
+		t.Errorf("zero time: got %x, want 0000000000000000", data[0:8])
+	}
+	var out StandardInformation
+	if err := out.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !out.FileCreation.IsZero() {
+		t.Errorf("zero time: got %v", out.FileCreation)
+	}
+	if !out.FileModification.Equal(info.FileModification) {
+		t.Errorf("time: got %v, want %v", out.FileModification, info.FileModification)
+	}
+}
+
+func TestUnpairedSurrogate(t *testing.T) {
+	tests := []struct {
+		name  string
+		utf16 string
+	}{
+		{"High", "4100" + "00d8" + "4200"},
+		{"Low", "4100" + "00dc" + "4200"},
+		{"Reversed", "00dc" + "00d8"},
+		{"Trailing", "4100" + "ffdb"},
+		{"Pair", "3dd8" + "00de"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, _ := hex.DecodeString(test.utf16)
+			s, err := utf16ToString(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out := stringToUTF16(s); !bytes.Equal(out, data) {
+				t.Errorf("%q: got %x, want %x", s, out, data)
+			}
+		})
+	}
+}
@@ -18,30 +18,77 @@ type MFT struct {
 	BaseAddr    int64 // In bytes
 }
 
-// File retrieves information about the file identified by id.
-func (mft *MFT) File(r io.ReadSeeker, id int64) (*File, error) {
-	var (
-		f       File
-		segment = make([]byte, mft.RecordSize)
-	)
+// Record retrieves the raw data of the file record segment identified by id.
+// The update sequence fixups will have been applied to the returned data.
+func (mft *MFT) Record(r io.ReadSeeker, id int64) ([]byte, error) {
+	if id < 0 {
+		return nil, fmt.Errorf("unable to read MFT file record data for entry %d: %w", id, ErrInvalidRecordNumber)
+	}
+
+	segment := make([]byte, mft.RecordSize)
 
 	// Read in the entire segment
-	r.Seek(mft.BaseAddr+mft.RecordSize*id, io.SeekStart)
-	if _, err := r.Read(segment); err != nil {
+	if _, err := r.Seek(mft.BaseAddr+mft.RecordSize*id, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("unable to seek to MFT file record data for entry %d: %v", id, err)
+	}
+	if _, err := io.ReadFull(r, segment); err != nil {
 		return nil, fmt.Errorf("unable to read MFT file record data for entry %d: %v", id, err)
 	}
 
+	// Records that have never been used are entirely zero
+	if unused(segment) {
+		return nil, fmt.Errorf("unable to parse file record for entry %d: %w", id, ErrUnusedRecord)
+	}
+
+	// Apply the update sequence fixups
+	var header MultiSectorHeader
+	if err := header.UnmarshalBinary(segment); err != nil {
+		return nil, fmt.Errorf("unable to parse multi-sector header for entry %d: %v", id, err)
+	}
+	if header.Signature != FileSignature {
+		return nil, fmt.Errorf("unable to parse file record for entry %d: %v", id, ErrInvalidFileSignature)
+	}
+	if err := header.ApplyFixups(segment); err != nil {
+		return nil, fmt.Errorf("unable to apply fixups to file record for entry %d: %v", id, err)
+	}
+
+	return segment, nil
+}
+
+// unused returns true if every byte of segment is zero.
+func unused(segment []byte) bool {
+	for _, b := range segment {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// File retrieves information about the file identified by id.
+func (mft *MFT) File(r io.ReadSeeker, id int64) (*File, error) {
+	segment, err := mft.Record(r, id)
+	if err != nil {
+		return nil, err
+	}
+	return parseFile(id, segment)
+}
+
+// parseFile parses the file record segment identified by id, which has
+// already had its fixups applied.
+func parseFile(id int64, segment []byte) (*File, error) {
+	f := File{ID: id}
+
 	// Unmarshal the file record segment header
 	if err := f.Header.UnmarshalBinary(segment); err != nil {
 		return nil, fmt.Errorf("unable to parse file record header for entry %d: %v", id, err)
 	}
 
-	//r.Seek(int64(f.Header.FirstAttributeOffset), io.SeekCurrent)
-
 	// Unmarshal attributes
 	pos := int64(f.Header.FirstAttributeOffset)
+	size := int64(len(segment))
 	a := 0
-	for pos < mft.RecordSize {
+	for pos < size {
 		data := segment[pos:]
 
 		// Detect the end of an attribute stream
@@ -56,6 +103,9 @@ func (mft *MFT) File(r io.ReadSeeker, id int64) (*File, error) {
 		if err := attr.UnmarshalBinary(data); err != nil {
 			return nil, fmt.Errorf("unable to read MFT attribute %d of file record %d: %v", a, id, err)
 		}
+		if attr.Header.RecordLength == 0 {
+			return nil, fmt.Errorf("unable to read MFT attribute %d of file record %d: %v", a, id, ErrAttributeLengthInvalid)
+		}
 
 		f.Attributes = append(f.Attributes, attr)
 		pos += int64(attr.Header.RecordLength)
@@ -0,0 +1,27 @@
+package ntfs
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestRecordNegativeID(t *testing.T) {
+	mft := MFT{SectorSize: 512, RecordSize: 1024}
+	r := bytes.NewReader(make([]byte, 4096))
+	if _, err := mft.Record(r, -1); !errors.Is(err, ErrInvalidRecordNumber) {
+		t.Errorf("Record(-1): got %v, want %v", err, ErrInvalidRecordNumber)
+	}
+	var reader Reader
+	if _, err := reader.File(-1); !errors.Is(err, ErrInvalidRecordNumber) {
+		t.Errorf("File(-1): got %v, want %v", err, ErrInvalidRecordNumber)
+	}
+}
+
+func TestRecordUnused(t *testing.T) {
+	mft := MFT{SectorSize: 512, RecordSize: 1024}
+	r := bytes.NewReader(make([]byte, 4096))
+	if _, err := mft.Record(r, 1); !errors.Is(err, ErrUnusedRecord) {
+		t.Errorf("got %v, want %v", err, ErrUnusedRecord)
+	}
+}
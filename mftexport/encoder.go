@@ -0,0 +1,148 @@
+package mftexport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Encoder writes records to an output stream.
+type Encoder interface {
+	// Encode writes record to the output stream.
+	Encode(record *Record) error
+
+	// Flush writes any buffered data to the output stream.
+	Flush() error
+}
+
+// CSVHeader holds the column names written by CSVEncoder.
+//
+// The name, namespace, parent and fn_ columns describe the preferred name
+// of the file. The names and streams columns list every name and $DATA
+// stream separated by vertical bars.
+var CSVHeader = []string{
+	"record", "sequence", "in_use", "directory", "flags", "base_record", "base_sequence", "lsn", "hard_links",
+	"path", "name", "namespace", "parent_record", "parent_sequence",
+	"created", "modified", "mft_modified", "accessed",
+	"fn_created", "fn_modified", "fn_mft_modified", "fn_accessed",
+	"file_attributes", "owner_id", "security_id", "usn", "size", "allocated_size",
+	"names", "streams", "stream_flags", "reparse_tag", "object_id", "error",
+}
+
+// CSVEncoder writes records as comma-separated values, one line per record.
+// A header line is written before the first record.
+type CSVEncoder struct {
+	w      *csv.Writer
+	header bool
+}
+
+// NewCSVEncoder returns a new CSV encoder that writes to w.
+func NewCSVEncoder(w io.Writer) *CSVEncoder {
+	return &CSVEncoder{w: csv.NewWriter(w)}
+}
+
+// Encode writes record to the output stream.
+func (enc *CSVEncoder) Encode(record *Record) error {
+	if !enc.header {
+		if err := enc.w.Write(CSVHeader); err != nil {
+			return err
+		}
+		enc.header = true
+	}
+
+	var name Name
+	for _, n := range record.Names {
+		if n.Preferred {
+			name = n
+			break
+		}
+	}
+	var names, streams, streamFlags []string
+	for _, n := range record.Names {
+		names = append(names, n.Name)
+	}
+	for _, s := range record.Streams {
+		if s.Name == "" {
+			streams = append(streams, "$DATA")
+		} else {
+			streams = append(streams, s.Name)
+		}
+		streamFlags = append(streamFlags, s.Flags)
+	}
+
+	return enc.w.Write([]string{
+		strconv.FormatInt(record.Record, 10),
+		strconv.FormatUint(uint64(record.Sequence), 10),
+		strconv.FormatBool(record.InUse),
+		strconv.FormatBool(record.Directory),
+		record.Flags,
+		strconv.FormatInt(record.BaseRecord, 10),
+		strconv.FormatUint(uint64(record.BaseSequence), 10),
+		strconv.FormatUint(record.LSN, 10),
+		strconv.FormatUint(uint64(record.HardLinks), 10),
+		record.Path,
+		name.Name,
+		name.Namespace,
+		strconv.FormatInt(name.ParentRecord, 10),
+		strconv.FormatUint(uint64(name.ParentSequence), 10),
+		formatTime(record.Created),
+		formatTime(record.Modified),
+		formatTime(record.MFTModified),
+		formatTime(record.Accessed),
+		formatTime(name.Created),
+		formatTime(name.Modified),
+		formatTime(name.MFTModified),
+		formatTime(name.Accessed),
+		record.FileAttributes,
+		strconv.FormatUint(uint64(record.OwnerID), 10),
+		strconv.FormatUint(uint64(record.SecurityID), 10),
+		strconv.FormatUint(record.USN, 10),
+		strconv.FormatInt(record.Size, 10),
+		strconv.FormatInt(record.AllocatedSize, 10),
+		strings.Join(names, "|"),
+		strings.Join(streams, "|"),
+		strings.Join(streamFlags, "|"),
+		"0x" + strconv.FormatUint(uint64(record.ReparseTag), 16),
+		record.ObjectID,
+		record.Error,
+	})
+}
+
+// Flush writes any buffered data to the output stream.
+func (enc *CSVEncoder) Flush() error {
+	enc.w.Flush()
+	return enc.w.Error()
+}
+
+// JSONEncoder writes records as JSON Lines, one JSON object per line.
+type JSONEncoder struct {
+	enc *json.Encoder
+}
+
+// NewJSONEncoder returns a new JSON Lines encoder that writes to w.
+func NewJSONEncoder(w io.Writer) *JSONEncoder {
+	return &JSONEncoder{enc: json.NewEncoder(w)}
+}
+
+// Encode writes record to the output stream.
+func (enc *JSONEncoder) Encode(record *Record) error {
+	return enc.enc.Encode(record)
+}
+
+// Flush writes any buffered data to the output stream. JSON encoders
+// don't buffer data, so it always returns nil.
+func (enc *JSONEncoder) Flush() error {
+	return nil
+}
+
+// formatTime returns t in RFC 3339 format with nanosecond precision, or
+// an empty string if t is zero.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,33 @@
+package mftexport
+
+import (
+	"errors"
+
+	"github.com/gentlemanautomaton/ntfs"
+)
+
+// Export writes a record for every file record in the master file table
+// of r to enc, in order. Records are streamed, so memory use doesn't grow
+// with the size of the table.
+//
+// Records that can't be read are written with only their record number
+// and the Error field set. Records that have never been used are skipped.
+// The encoder is flushed before Export returns.
+func Export(r *ntfs.Reader, enc Encoder) error {
+	resolver := r.NewPathResolver()
+	id := int64(-1)
+	err := r.Walk(func(file *ntfs.File, err error) error {
+		id++
+		if errors.Is(err, ntfs.ErrUnusedRecord) {
+			return nil
+		}
+		if err != nil {
+			return enc.Encode(&Record{Record: id, Error: err.Error()})
+		}
+		return enc.Encode(NewRecord(r, resolver, file))
+	})
+	if flushErr := enc.Flush(); err == nil {
+		err = flushErr
+	}
+	return err
+}
@@ -0,0 +1,182 @@
+// Package mftexport exports the records of an NTFS master file table in
+// tabular and line-oriented formats.
+package mftexport
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/gentlemanautomaton/ntfs"
+	"github.com/gentlemanautomaton/ntfs/attrtype"
+)
+
+// Record is a flattened description of a file record.
+type Record struct {
+	Record         int64     `json:"record"`
+	Sequence       uint16    `json:"sequence"`
+	InUse          bool      `json:"in_use"`
+	Directory      bool      `json:"directory"`
+	Flags          string    `json:"flags"`
+	BaseRecord     int64     `json:"base_record"` // Zero unless the record is an extension record
+	BaseSequence   uint16    `json:"base_sequence"`
+	LSN            uint64    `json:"lsn"`
+	HardLinks      uint16    `json:"hard_links"`
+	Path           string    `json:"path"`
+	Names          []Name    `json:"names"`
+	Created        time.Time `json:"created"`
+	Modified       time.Time `json:"modified"`
+	MFTModified    time.Time `json:"mft_modified"`
+	Accessed       time.Time `json:"accessed"`
+	FileAttributes string    `json:"file_attributes"`
+	OwnerID        uint32    `json:"owner_id"`
+	SecurityID     uint32    `json:"security_id"`
+	USN            uint64    `json:"usn"`
+	Size           int64     `json:"size"` // The size of the default $DATA stream
+	AllocatedSize  int64     `json:"allocated_size"`
+	Streams        []Stream  `json:"streams"`
+	ReparseTag     uint32    `json:"reparse_tag"`
+	ObjectID       string    `json:"object_id"`
+	Error          string    `json:"error,omitempty"` // Describes information that couldn't be read
+}
+
+// Name describes a $FILE_NAME attribute of a file record.
+type Name struct {
+	Name           string    `json:"name"`
+	Namespace      string    `json:"namespace"`
+	ParentRecord   int64     `json:"parent_record"`
+	ParentSequence uint16    `json:"parent_sequence"`
+	Created        time.Time `json:"created"`
+	Modified       time.Time `json:"modified"`
+	MFTModified    time.Time `json:"mft_modified"`
+	Accessed       time.Time `json:"accessed"`
+	Preferred      bool      `json:"preferred"` // The name chosen by ntfs.PreferredName
+}
+
+// Stream describes a $DATA stream of a file record.
+type Stream struct {
+	Name          string `json:"name"`
+	Size          int64  `json:"size"`
+	AllocatedSize int64  `json:"allocated_size"`
+	Resident      bool   `json:"resident"`
+	Flags         string `json:"flags"` // Compressed, encrypted or sparse
+}
+
+// NewRecord returns a flattened description of file, which must have been
+// retrieved from r. The path of the file is resolved by resolver.
+//
+// Problems reading parts of the record are described by the Error field
+// of the returned record. Extension records only include header
+// information, as their attributes belong to their base record.
+func NewRecord(r *ntfs.Reader, resolver *ntfs.PathResolver, file *ntfs.File) *Record {
+	header := &file.Header
+	record := &Record{
+		Record:    file.ID,
+		Sequence:  header.SequenceNumber,
+		InUse:     header.InUse(),
+		Directory: header.Directory(),
+		Flags:     header.Flags.String(),
+		LSN:       header.LogFileSequenceNumber,
+		HardLinks: header.HardLinkCount,
+	}
+	if !header.BaseFileRecordSegment.IsZero() {
+		record.BaseRecord = header.BaseFileRecordSegment.SegmentNumber()
+		record.BaseSequence = header.BaseFileRecordSegment.SequenceNumber
+		return record
+	}
+
+	if attr, ok := file.Attribute(attrtype.StandardInformation); ok {
+		var si ntfs.StandardInformation
+		if err := si.UnmarshalBinary(attr.ResidentValue); err != nil {
+			record.addError("standard information", err)
+		} else {
+			record.Created = si.FileCreation
+			record.Modified = si.FileModification
+			record.MFTModified = si.MFTModification
+			record.Accessed = si.FileRead
+			record.FileAttributes = si.DOSFilePermissions.String()
+			record.OwnerID = si.OwnerID
+			record.SecurityID = si.SecurityID
+			record.USN = si.USN
+		}
+	}
+
+	names, err := r.Names(file)
+	if err != nil {
+		record.addError("file names", err)
+	}
+	preferred := -1
+	if best, ok := ntfs.PreferredName(names); ok {
+		for i := range names {
+			if names[i] == best {
+				preferred = i
+				break
+			}
+		}
+	}
+	for i, fn := range names {
+		record.Names = append(record.Names, Name{
+			Name:           fn.Value,
+			Namespace:      fn.Flags.Namespace(),
+			ParentRecord:   fn.ParentDirectory.SegmentNumber(),
+			ParentSequence: fn.ParentDirectory.SequenceNumber,
+			Created:        fn.FileCreation,
+			Modified:       fn.FileModification,
+			MFTModified:    fn.MFTModification,
+			Accessed:       fn.FileRead,
+			Preferred:      i == preferred,
+		})
+	}
+	if len(names) > 0 || file.ID == ntfs.RecordRoot {
+		if path, err := resolver.Path(file); err == nil {
+			record.Path = path
+		}
+	}
+
+	streams, err := r.Streams(file)
+	if err != nil {
+		record.addError("streams", err)
+	}
+	for _, stream := range streams {
+		if stream.Name == "" {
+			record.Size = stream.Size
+			record.AllocatedSize = stream.AllocatedSize
+		}
+		record.Streams = append(record.Streams, Stream{
+			Name:          stream.Name,
+			Size:          stream.Size,
+			AllocatedSize: stream.AllocatedSize,
+			Resident:      stream.Resident,
+			Flags:         stream.Flags.String(),
+		})
+	}
+
+	if attr, ok := file.Attribute(attrtype.ReparsePoint); ok {
+		data, err := r.ReadAttribute(attr)
+		if err != nil {
+			record.addError("reparse point", err)
+		} else if len(data) >= 4 {
+			record.ReparseTag = binary.LittleEndian.Uint32(data[0:4])
+		}
+	}
+
+	if attr, ok := file.Attribute(attrtype.ObjectID); ok {
+		var id ntfs.ObjectID
+		if err := id.UnmarshalBinary(attr.ResidentValue); err != nil {
+			record.addError("object ID", err)
+		} else {
+			record.ObjectID = id.Value.String()
+		}
+	}
+
+	return record
+}
+
+// addError records a problem reading part of a file record.
+func (record *Record) addError(part string, err error) {
+	msg := "unable to read " + part + ": " + err.Error()
+	if record.Error != "" {
+		record.Error += "; " + msg
+	} else {
+		record.Error = msg
+	}
+}
@@ -0,0 +1,90 @@
+package ntfs
+
+import "io"
+
+// recordSizes are the file record sizes tried when the size can't be read
+// from the first record of a standalone master file table.
+var recordSizes = []int64{1024, 4096, 512, 2048}
+
+// OpenMFT returns a reader for a standalone copy of a master file table,
+// such as a $MFT file collected by a triage tool, without the volume it
+// came from.
+//
+// The file record size is inferred from the header of the first record.
+// Record-level information, such as file names, paths, timestamps and
+// resident attribute data, is available. Non-resident attribute data, the
+// extents of streams and checks that need the volume, such as Check and
+// CheckMirror, can't be read and return ErrNoVolume.
+func OpenMFT(rs io.ReadSeeker) (*Reader, error) {
+	size, err := rs.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	recordSize, err := inferRecordSize(rs)
+	if err != nil {
+		return nil, err
+	}
+	r := &Reader{
+		mft: MFT{
+			SectorSize: UpdateSequenceStride,
+			RecordSize: recordSize,
+		},
+		mftr:    rs,
+		records: size / recordSize,
+	}
+	return r, nil
+}
+
+// Standalone returns true if the reader was opened on a standalone master
+// file table without its volume.
+func (r *Reader) Standalone() bool {
+	return r.r == nil
+}
+
+// inferRecordSize returns the size of the file records in the master file
+// table read by rs.
+//
+// The allocated size in the header of the first record is used if it is
+// plausible. Otherwise common record sizes are tried until one is found
+// at which the next two records begin with a file record signature.
+func inferRecordSize(rs io.ReadSeeker) (int64, error) {
+	var header [FileRecordSegmentHeaderLength]byte
+	if err := readFull(rs, 0, header[:]); err == nil {
+		var h FileRecordSegmentHeader
+		if h.UnmarshalBinary(header[:]) == nil && h.Signature == FileSignature {
+			size := int64(h.AllocatedSize)
+			if validRecordSize(size) && int64(h.UpdateSequenceArraySize) == size/UpdateSequenceStride+1 {
+				return size, nil
+			}
+		}
+	}
+
+	var sig [4]byte
+	for _, size := range recordSizes {
+		found := true
+		for _, n := range []int64{1, 2} {
+			if err := readFull(rs, size*n, sig[:]); err != nil || sig != FileSignature {
+				found = false
+				break
+			}
+		}
+		if found {
+			return size, nil
+		}
+	}
+	return 0, ErrRecordSizeUnknown
+}
+
+// validRecordSize returns true if size is a plausible file record size.
+func validRecordSize(size int64) bool {
+	return size >= 512 && size <= 65536 && size&(size-1) == 0
+}
+
+// readFull reads len(p) bytes from rs at offset.
+func readFull(rs io.ReadSeeker, offset int64, p []byte) error {
+	if _, err := rs.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	_, err := io.ReadFull(rs, p)
+	return err
+}
@@ -0,0 +1,54 @@
+package ntfs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// testMFT returns a master file table of count records of recordSize
+// bytes. Each record begins with a file record signature and the first
+// record's header claims an allocated size of headerSize bytes.
+func testMFT(recordSize, headerSize int64, count int) []byte {
+	data := make([]byte, recordSize*int64(count))
+	for i := 0; i < count; i++ {
+		copy(data[recordSize*int64(i):], FileSignature[:])
+	}
+	binary.LittleEndian.PutUint16(data[4:6], FileRecordSegmentHeaderExtendedLength)
+	binary.LittleEndian.PutUint16(data[6:8], uint16(headerSize/UpdateSequenceStride+1))
+	binary.LittleEndian.PutUint32(data[28:32], uint32(headerSize))
+	return data
+}
+
+func TestInferRecordSize(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		size int64
+		err  error
+	}{
+		{"Header1024", testMFT(1024, 1024, 3), 1024, nil},
+		{"Header4096", testMFT(4096, 4096, 3), 4096, nil},
+		{"HeaderOnly", testMFT(4096, 4096, 1), 4096, nil},
+		{"ProbeImplausibleSize", testMFT(4096, 3000, 3), 4096, nil},
+		{"ProbeZeroSize", testMFT(2048, 0, 3), 2048, nil},
+		{"ProbeDamagedHeader", func() []byte {
+			data := testMFT(1024, 1024, 3)
+			data[0] = 0
+			return data
+		}(), 1024, nil},
+		{"Unknown", testMFT(1024, 0, 1), 0, ErrRecordSizeUnknown},
+		{"Empty", nil, 0, ErrRecordSizeUnknown},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			size, err := inferRecordSize(bytes.NewReader(test.data))
+			if err != test.err {
+				t.Fatalf("got error %v, want %v", err, test.err)
+			}
+			if size != test.size {
+				t.Errorf("got size %d, want %d", size, test.size)
+			}
+		})
+	}
+}
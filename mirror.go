@@ -0,0 +1,138 @@
+package ntfs
+
+import (
+	"fmt"
+
+	"github.com/gentlemanautomaton/ntfs/attrtype"
+)
+
+// mirrorRecords is the number of file records that are always present in
+// the $MFTMirr system file. These records hold $MFT, $MFTMirr, $LogFile and
+// $Volume.
+const mirrorRecords = 4
+
+// MirrorDifference describes a file record that differs between the master
+// file table and its mirror.
+type MirrorDifference struct {
+	Record    int64 // The file record number
+	Offset    int   // The offset of the first differing byte, or -1
+	MFTErr    error // The error encountered while reading the $MFT copy
+	MirrorErr error // The error encountered while reading the $MFTMirr copy
+}
+
+// String returns a description of the difference.
+func (diff MirrorDifference) String() string {
+	switch {
+	case diff.MFTErr != nil && diff.MirrorErr != nil:
+		return fmt.Sprintf("record %d: $MFT: %v, $MFTMirr: %v", diff.Record, diff.MFTErr, diff.MirrorErr)
+	case diff.MFTErr != nil:
+		return fmt.Sprintf("record %d: $MFT: %v", diff.Record, diff.MFTErr)
+	case diff.MirrorErr != nil:
+		return fmt.Sprintf("record %d: $MFTMirr: %v", diff.Record, diff.MirrorErr)
+	default:
+		return fmt.Sprintf("record %d: data differs at offset %d", diff.Record, diff.Offset)
+	}
+}
+
+// MirrorFallback returns an option that causes a Reader to substitute the
+// copies of file records 0 through 3 held in $MFTMirr when the primary
+// copies in $MFT cannot be read or parsed. A warning is recorded the first
+// time each mirror copy is used.
+func MirrorFallback() Option {
+	return func(r *Reader) {
+		r.mirrorFallback = true
+	}
+}
+
+// Mirror returns an MFT that provides access to the file records in the
+// $MFTMirr system file. Records must be read from the volume itself.
+func (r *Reader) Mirror() MFT {
+	mirror := r.mft
+	mirror.BaseAddr = int64(r.boot.MFTMirror) * int64(r.boot.ClusterSize())
+	return mirror
+}
+
+// CheckMirror compares the file records held in $MFTMirr with their
+// counterparts in $MFT after fixups have been applied. It returns a
+// difference for each record that could not be read or does not match.
+//
+// The update sequence arrays of the records are excluded from the
+// comparison. Standalone readers return ErrNoVolume.
+func (r *Reader) CheckMirror() ([]MirrorDifference, error) {
+	if r.Standalone() {
+		return nil, ErrNoVolume
+	}
+	mirror := r.Mirror()
+
+	count, err := r.mirrorCount()
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []MirrorDifference
+	for id := int64(0); id < count; id++ {
+		primary, mftErr := r.mft.Record(r.mftr, id)
+		mirrored, mirrorErr := mirror.Record(r.r, id)
+		if mftErr != nil || mirrorErr != nil {
+			diffs = append(diffs, MirrorDifference{
+				Record:    id,
+				Offset:    -1,
+				MFTErr:    mftErr,
+				MirrorErr: mirrorErr,
+			})
+			continue
+		}
+		maskUpdateSequence(primary)
+		maskUpdateSequence(mirrored)
+		for i := range primary {
+			if primary[i] != mirrored[i] {
+				diffs = append(diffs, MirrorDifference{
+					Record: id,
+					Offset: i,
+				})
+				break
+			}
+		}
+	}
+
+	return diffs, nil
+}
+
+// mirrorCount returns the number of file records held in $MFTMirr, as
+// determined by the size of its data. If the size can't be determined the
+// minimum of four records is assumed.
+func (r *Reader) mirrorCount() (int64, error) {
+	if r.mft.RecordSize <= 0 {
+		return 0, ErrInvalidParameterBlock
+	}
+	file, err := r.File(RecordMFTMirr)
+	if err != nil {
+		return mirrorRecords, nil
+	}
+	attr, ok := file.Attribute(attrtype.Data)
+	if !ok || attr.Header.Resident() {
+		return mirrorRecords, nil
+	}
+	count := attr.Nonresident.DataLength / r.mft.RecordSize
+	if count < mirrorRecords {
+		return mirrorRecords, nil
+	}
+	return count, nil
+}
+
+// maskUpdateSequence zeroes the update sequence array of a multi-sector
+// record.
+func maskUpdateSequence(data []byte) {
+	var header MultiSectorHeader
+	if err := header.UnmarshalBinary(data); err != nil {
+		return
+	}
+	start := int(header.UpdateSequenceArrayOffset)
+	end := start + int(header.UpdateSequenceArraySize)*2
+	if end > len(data) {
+		return
+	}
+	for i := start; i < end; i++ {
+		data[i] = 0
+	}
+}
@@ -1,7 +1,111 @@
 package mspart
 
+import "fmt"
+
+// https://docs.microsoft.com/windows/win32/api/winioctl/ns-winioctl-partition_information_gpt
+
+// GUID partition table partition types, in their on-disk byte order. The
+// comments give each GUID in its string form.
 var (
-	// BasicData is the partition type of basic data partitions in
-	// GUID partition tables.
-	BasicData = [16]byte{0xa2, 0xa0, 0xd0, 0xeB, 0xe5, 0xb9, 0x33, 0x44, 0x87, 0xc0, 0x68, 0xb6, 0xb7, 0x26, 0x99, 0xc7} // EBD0A0A2-B9E5-4433-87C0-68B6B72699C7
+	BasicData           = [16]byte{0xa2, 0xa0, 0xd0, 0xeb, 0xe5, 0xb9, 0x33, 0x44, 0x87, 0xc0, 0x68, 0xb6, 0xb7, 0x26, 0x99, 0xc7} // EBD0A0A2-B9E5-4433-87C0-68B6B72699C7
+	MicrosoftReserved   = [16]byte{0x16, 0xe3, 0xc9, 0xe3, 0x5c, 0x0b, 0xb8, 0x4d, 0x81, 0x7d, 0xf9, 0x2d, 0xf0, 0x02, 0x15, 0xae} // E3C9E316-0B5C-4DB8-817D-F92DF00215AE
+	LDMMetadata         = [16]byte{0xaa, 0xc8, 0x08, 0x58, 0x8f, 0x7e, 0xe0, 0x42, 0x85, 0xd2, 0xe1, 0xe9, 0x04, 0x34, 0xcf, 0xb3} // 5808C8AA-7E8F-42E0-85D2-E1E90434CFB3
+	LDMData             = [16]byte{0xa0, 0x60, 0x9b, 0xaf, 0x31, 0x14, 0x62, 0x4f, 0xbc, 0x68, 0x33, 0x11, 0x71, 0x4a, 0x69, 0xad} // AF9B60A0-1431-4F62-BC68-3311714A69AD
+	RecoveryEnvironment = [16]byte{0xa4, 0xbb, 0x94, 0xde, 0xd1, 0x06, 0x40, 0x4d, 0xa1, 0x6a, 0xbf, 0xd5, 0x01, 0x79, 0xd6, 0xac} // DE94BBA4-06D1-4D40-A16A-BFD50179D6AC
+	StorageSpaces       = [16]byte{0x8f, 0xaf, 0x5c, 0xe7, 0x80, 0xf6, 0xee, 0x4c, 0xaf, 0xa3, 0xb0, 0x01, 0xe5, 0x6e, 0xfc, 0x2d} // E75CAF8F-F680-4CEE-AFA3-B001E56EFC2D
+	EFISystem           = [16]byte{0x28, 0x73, 0x2a, 0xc1, 0x1f, 0xf8, 0xd2, 0x11, 0xba, 0x4b, 0x00, 0xa0, 0xc9, 0x3e, 0xc9, 0x3b} // C12A7328-F81F-11D2-BA4B-00A0C93EC93B
+)
+
+// MBR is a master boot record partition type byte.
+type MBR byte
+
+// Master boot record partition types.
+//
+// https://docs.microsoft.com/windows/win32/fileio/disk-partition-types
+const (
+	Empty              MBR = 0x00
+	FAT12              MBR = 0x01
+	FAT16              MBR = 0x04
+	Extended           MBR = 0x05 // Extended partition using CHS addressing
+	FAT16B             MBR = 0x06
+	IFS                MBR = 0x07 // NTFS, exFAT or another installable file system
+	FAT32              MBR = 0x0B
+	FAT32LBA           MBR = 0x0C
+	FAT16LBA           MBR = 0x0E
+	ExtendedLBA        MBR = 0x0F // Extended partition using LBA addressing
+	HiddenIFS          MBR = 0x17
+	WindowsRecovery    MBR = 0x27
+	LDM                MBR = 0x42 // Logical disk manager partition on a dynamic disk
+	LinuxExtended      MBR = 0x85
+	ProtectiveMBR      MBR = 0xEE // Protective partition covering a GUID partition table
+	EFISystemPartition MBR = 0xEF
 )
+
+// Extended returns true if t is an extended partition type, which holds
+// a chain of logical partitions.
+func (t MBR) Extended() bool {
+	return t == Extended || t == ExtendedLBA || t == LinuxExtended
+}
+
+// String returns a description of the partition type.
+func (t MBR) String() string {
+	switch t {
+	case Empty:
+		return "Empty"
+	case FAT12:
+		return "FAT12"
+	case FAT16:
+		return "FAT16"
+	case Extended:
+		return "Extended"
+	case FAT16B:
+		return "FAT16B"
+	case IFS:
+		return "IFS"
+	case FAT32:
+		return "FAT32"
+	case FAT32LBA:
+		return "FAT32 LBA"
+	case FAT16LBA:
+		return "FAT16 LBA"
+	case ExtendedLBA:
+		return "Extended LBA"
+	case HiddenIFS:
+		return "Hidden IFS"
+	case WindowsRecovery:
+		return "Windows Recovery"
+	case LDM:
+		return "LDM"
+	case LinuxExtended:
+		return "Linux Extended"
+	case ProtectiveMBR:
+		return "Protective MBR"
+	case EFISystemPartition:
+		return "EFI System"
+	default:
+		return fmt.Sprintf("%#02x", byte(t))
+	}
+}
+
+// GPTName returns the name of the GUID partition table partition type t.
+// If t is not a known Microsoft partition type an empty string is returned.
+func GPTName(t [16]byte) string {
+	switch t {
+	case BasicData:
+		return "Basic Data"
+	case MicrosoftReserved:
+		return "Microsoft Reserved"
+	case LDMMetadata:
+		return "LDM Metadata"
+	case LDMData:
+		return "LDM Data"
+	case RecoveryEnvironment:
+		return "Windows Recovery"
+	case StorageSpaces:
+		return "Storage Spaces"
+	case EFISystem:
+		return "EFI System"
+	default:
+		return ""
+	}
+}
@@ -5,6 +5,10 @@ import "encoding/binary"
 // MultiSectorHeaderLength is the length of a multi-sector header in bytes.
 const MultiSectorHeaderLength = 8
 
+// UpdateSequenceStride is the number of bytes protected by each entry in an
+// update sequence array.
+const UpdateSequenceStride = 512
+
 // MultiSectorHeader specifies the location and size of an update sequence
 // array.
 //
@@ -31,3 +35,48 @@ func (header *MultiSectorHeader) UnmarshalBinary(data []byte) error {
 	header.UpdateSequenceArraySize = binary.LittleEndian.Uint16(data[6:8])
 	return nil
 }
+
+// MarshalBinary returns the little-endian binary representation of header.
+func (header *MultiSectorHeader) MarshalBinary() ([]byte, error) {
+	data := make([]byte, MultiSectorHeaderLength)
+	header.marshal(data)
+	return data, nil
+}
+
+func (header *MultiSectorHeader) marshal(data []byte) {
+	copy(data[0:4], header.Signature[:])
+	binary.LittleEndian.PutUint16(data[4:6], header.UpdateSequenceArrayOffset)
+	binary.LittleEndian.PutUint16(data[6:8], header.UpdateSequenceArraySize)
+}
+
+// ApplyFixups verifies and removes the update sequence values at the end of
+// each 512-byte stride of data, restoring the original bytes from the update
+// sequence array. The data is modified in place.
+//
+// If the last two bytes of a stride don't match the update sequence number
+// the multi-sector transfer was incomplete and ErrFixupMismatch is returned.
+func (header *MultiSectorHeader) ApplyFixups(data []byte) error {
+	if header.UpdateSequenceArraySize == 0 {
+		return nil
+	}
+	start := int(header.UpdateSequenceArrayOffset)
+	end := start + int(header.UpdateSequenceArraySize)*2
+	if end > len(data) {
+		return ErrFixupOutOfBounds
+	}
+	strides := int(header.UpdateSequenceArraySize) - 1
+	if strides*UpdateSequenceStride > len(data) {
+		return ErrFixupOutOfBounds
+	}
+	usn := data[start : start+2]
+	for i := 0; i < strides; i++ {
+		pos := (i+1)*UpdateSequenceStride - 2
+		if data[pos] != usn[0] || data[pos+1] != usn[1] {
+			return ErrFixupMismatch
+		}
+		entry := start + 2 + i*2
+		data[pos] = data[entry]
+		data[pos+1] = data[entry+1]
+	}
+	return nil
+}
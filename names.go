@@ -0,0 +1,177 @@
+package ntfs
+
+import (
+	"strings"
+
+	"github.com/gentlemanautomaton/ntfs/attrtype"
+	"github.com/gentlemanautomaton/ntfs/filenameflag"
+)
+
+// PathSeparator is the separator used in paths returned by the reader.
+const PathSeparator = `\`
+
+// Names returns the file name attributes of file. Each attribute describes
+// a hard link to the file or a DOS 8.3 alias of one.
+//
+// File name attributes stored in extension records are only included if
+// the file was retrieved from a Reader.
+func (file *File) Names() ([]FileName, error) {
+	if file.reader != nil {
+		return file.reader.Names(file)
+	}
+	if _, ok := file.Attribute(attrtype.AttributeList); ok {
+		return nil, ErrNoReader
+	}
+	return unmarshalNames(file.Attributes)
+}
+
+// Paths returns the full path of every hard link to file.
+func (file *File) Paths() ([]string, error) {
+	if file.reader == nil {
+		return nil, ErrNoReader
+	}
+	return file.reader.Paths(file)
+}
+
+// Names returns the file name attributes of file, including those stored
+// in extension records.
+func (r *Reader) Names(file *File) ([]FileName, error) {
+	entries, err := r.AttributeList(file)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		return unmarshalNames(file.Attributes)
+	}
+	attrs, err := r.Segments(file, attrtype.FileName, "")
+	if err == ErrAttributeMissing {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalNames(attrs)
+}
+
+// Paths returns the full path of every hard link to file. DOS 8.3 aliases
+// are not included.
+//
+// Each path is built by following parent directory references up to the
+// root directory, using the preferred name of each directory along the
+// way.
+func (r *Reader) Paths(file *File) ([]string, error) {
+	if file.ID == RecordRoot {
+		return []string{PathSeparator}, nil
+	}
+	names, err := r.Names(file)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, name := range names {
+		if !name.Flags.Long() {
+			continue
+		}
+		dir, err := r.directoryPath(name.ParentDirectory)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, joinPath(dir, name.Value))
+	}
+	return paths, nil
+}
+
+// directoryPath returns the full path of the directory identified by ref.
+func (r *Reader) directoryPath(ref FileReference) (string, error) {
+	var (
+		parts   []string
+		visited = make(map[int64]bool)
+	)
+	for ref.SegmentNumber() != RecordRoot {
+		id := ref.SegmentNumber()
+		if visited[id] {
+			return "", ErrPathLoop
+		}
+		visited[id] = true
+
+		dir, err := r.File(id)
+		if err != nil {
+			return "", err
+		}
+		if !dir.Header.InUse() || !dir.Header.Directory() || dir.Header.SequenceNumber != ref.SequenceNumber {
+			return "", ErrInvalidParent
+		}
+		names, err := r.Names(dir)
+		if err != nil {
+			return "", err
+		}
+		name, ok := PreferredName(names)
+		if !ok {
+			return "", ErrInvalidParent
+		}
+		parts = append(parts, name.Value)
+		ref = name.ParentDirectory
+	}
+
+	// The parts were collected from the bottom up
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return PathSeparator + strings.Join(parts, PathSeparator), nil
+}
+
+// PreferredName returns the name that should be displayed for a file with
+// the given names. Win32 names are preferred, followed by POSIX names.
+// DOS 8.3 aliases are only returned when no other name is present.
+//
+// If names is empty ok will be false.
+func PreferredName(names []FileName) (name FileName, ok bool) {
+	best := -1
+	for i := range names {
+		if best < 0 || namespaceRank(names[i]) < namespaceRank(names[best]) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return FileName{}, false
+	}
+	return names[best], true
+}
+
+// namespaceRank returns the display preference of fn's namespace. Lower
+// values are preferred.
+func namespaceRank(fn FileName) int {
+	switch {
+	case fn.Flags&filenameflag.NTFS != 0:
+		return 0
+	case fn.Flags.Long():
+		return 1
+	default:
+		return 2
+	}
+}
+
+// unmarshalNames unmarshals the file name attributes in attrs.
+func unmarshalNames(attrs []Attribute) ([]FileName, error) {
+	var names []FileName
+	for a := range attrs {
+		attr := &attrs[a]
+		if attr.Header.TypeCode != attrtype.FileName {
+			continue
+		}
+		var fn FileName
+		if err := fn.UnmarshalBinary(attr.ResidentValue); err != nil {
+			return nil, err
+		}
+		names = append(names, fn)
+	}
+	return names, nil
+}
+
+// joinPath appends name to the directory path dir.
+func joinPath(dir, name string) string {
+	if strings.HasSuffix(dir, PathSeparator) {
+		return dir + name
+	}
+	return dir + PathSeparator + name
+}
@@ -1,24 +1,73 @@
 package ntfs
 
-import "io"
+import (
+	"fmt"
+	"io"
+
+	"github.com/gentlemanautomaton/ntfs/attrtype"
+)
 
 // Reader is an NTFS file system reader that supports NTFS file system versions
 // 3.0 and 3.1. It reads data from an underlying io.ReadSeeker that must not
 // include partition table data.
 type Reader struct {
-	r    io.ReadSeeker
-	boot BootRecord
+	r        io.ReadSeeker
+	boot     BootRecord
+	mft      MFT
+	mftr     io.ReadSeeker // Reads the data of the $MFT system file
+	records  int64         // The number of records in the $MFT
+	bad      []ClusterRange
+	warnings []error
+
+	dirtyPolicy    Policy
+	versionPolicy  Policy
+	mirrorFallback bool
+	mirrored       [mirrorRecords]bool // Records already read from $MFTMirr
 }
 
 // NewReader returns a new NTFS filesystem reader that reads from rs.
-// It will read the volume boot record before returning. If it cannot
-// read the volume boot record from rs, an error will be returned.
-func NewReader(rs io.ReadSeeker) (*Reader, error) {
+// It will read the volume boot record, the $DATA attribute of $MFT and the
+// list of bad clusters in $BadClus before returning. If it cannot read the
+// volume boot record from rs, a nil reader and an error will be returned.
+// Failures to read $MFT or $BadClus are recorded as warnings instead.
+//
+// The primary boot sector is validated and compared with the backup boot
+// sector in the last sector of the volume. If the primary boot sector is
+// invalid but the backup is intact, the reader falls back to the backup
+// and records a warning.
+//
+// If a dirty volume or unsupported version policy has been provided,
+// NewReader will also read the volume information in $Volume and apply
+// the policies before returning. If $Volume can't be read or a policy
+// refuses the volume, a nil reader and an error will be returned.
+func NewReader(rs io.ReadSeeker, options ...Option) (*Reader, error) {
 	r := &Reader{
 		r: rs,
 	}
-	_, err := r.boot.ReadFrom(rs)
-	return r, err
+	for _, option := range options {
+		option(r)
+	}
+	if err := r.readBootSector(); err != nil {
+		return nil, err
+	}
+	r.mft = MFT{
+		SectorSize:  int64(r.boot.BytesPerSector),
+		ClusterSize: int64(r.boot.ClusterSize()),
+		RecordSize:  int64(r.boot.FileRecordSize()),
+		BaseAddr:    int64(r.boot.MFT) * int64(r.boot.ClusterSize()),
+	}
+	r.mftr = rs
+	if err := r.loadMFT(); err != nil {
+		r.warn(fmt.Errorf("%w: %v", ErrMFTDataUnavailable, err))
+		r.records = r.contiguousRecordCount()
+	}
+	if err := r.loadBadClusters(); err != nil {
+		r.warn(fmt.Errorf("%w: %v", ErrBadClusterListUnavailable, err))
+	}
+	if err := r.applyVolumePolicies(); err != nil {
+		return nil, err
+	}
+	return r, nil
 }
 
 // BootRecord returns a copy of the volume boot record.
@@ -26,6 +75,223 @@ func (r *Reader) BootRecord() BootRecord {
 	return r.boot
 }
 
+// Warnings returns the warnings that have been recorded by the reader.
+// Warnings describe conditions that did not prevent the reader from
+// operating but may affect the reliability of the data it returns.
+func (r *Reader) Warnings() []error {
+	return r.warnings
+}
+
+// File retrieves the file record identified by id from the master file
+// table.
+//
+// If the reader was created with the MirrorFallback option and one of the
+// first four records can't be read, its copy in $MFTMirr will be returned
+// instead. A warning is recorded the first time each copy is used.
+func (r *Reader) File(id int64) (*File, error) {
+	if id < 0 {
+		return nil, fmt.Errorf("unable to read file record %d: %w", id, ErrInvalidRecordNumber)
+	}
+	file, err := r.mft.File(r.mftr, id)
+	if err != nil && r.mirrorFallback && id < mirrorRecords && !r.Standalone() {
+		mirror := r.Mirror()
+		if mirrored, mirrorErr := mirror.File(r.r, id); mirrorErr == nil {
+			if !r.mirrored[id] {
+				r.mirrored[id] = true
+				r.warn(fmt.Errorf("%w: record %d: %v", ErrMirrorRecordUsed, id, err))
+			}
+			file, err = mirrored, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	file.reader = r
+	return file, nil
+}
+
+// readBootSector reads and validates the primary and backup boot sectors
+// and loads the volume boot record from the first one that is valid.
+func (r *Reader) readBootSector() error {
+	var primary BootSector
+	if _, err := r.r.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := primary.ReadFrom(r.r); err != nil {
+		return err
+	}
+	primaryErr := primary.Validate()
+
+	backup, backupErr := r.readBackupBootSector(&primary, primaryErr == nil)
+
+	switch {
+	case primaryErr == nil:
+		if backupErr != nil {
+			r.warn(ErrBackupBootSectorInvalid)
+		} else if !primary.Equal(&backup) {
+			r.warn(ErrBootSectorMismatch)
+		}
+		r.boot, _ = primary.Record()
+	case backupErr == nil:
+		r.warn(fmt.Errorf("%w: %v", ErrBackupBootSectorUsed, primaryErr))
+		r.boot, _ = backup.Record()
+	default:
+		return primaryErr
+	}
+
+	return nil
+}
+
+// readBackupBootSector reads the backup boot sector from the last sector of
+// the volume.
+//
+// If the primary boot sector is valid its geometry is used to locate the
+// backup. Otherwise the end of the volume is searched for a valid backup
+// for each of the supported sector sizes.
+func (r *Reader) readBackupBootSector(primary *BootSector, primaryValid bool) (backup BootSector, err error) {
+	var offsets []int64
+	if primaryValid {
+		boot, _ := primary.Record()
+		offsets = append(offsets, int64(boot.TotalSectors)*int64(boot.BytesPerSector))
+	} else {
+		end, err := r.r.Seek(0, io.SeekEnd)
+		if err != nil {
+			return backup, err
+		}
+		for _, size := range []int64{512, 1024, 2048, 4096} {
+			if end-size > 0 {
+				offsets = append(offsets, end-size)
+			}
+		}
+	}
+
+	err = ErrBackupBootSectorInvalid
+	for _, offset := range offsets {
+		if _, err = r.r.Seek(offset, io.SeekStart); err != nil {
+			continue
+		}
+		if _, err = backup.ReadFrom(r.r); err != nil {
+			continue
+		}
+		if err = backup.Validate(); err == nil {
+			return backup, nil
+		}
+	}
+	return backup, err
+}
+
+// RecordCount returns the number of file records in the master file table,
+// including records that are not in use.
+func (r *Reader) RecordCount() int64 {
+	return r.records
+}
+
+// MFTBitmap returns the $BITMAP attribute of the $MFT system file, which
+// records the file records that are in use.
+func (r *Reader) MFTBitmap() (Bitmap, error) {
+	return r.systemBitmap(RecordMFT, attrtype.Bitmap)
+}
+
+// ClusterBitmap returns the data of the $Bitmap system file, which records
+// the clusters of the volume that are allocated.
+func (r *Reader) ClusterBitmap() (Bitmap, error) {
+	return r.systemBitmap(RecordBitmap, attrtype.Data)
+}
+
+// systemBitmap reads a bitmap from an attribute of a system file.
+func (r *Reader) systemBitmap(id int64, code attrtype.Code) (Bitmap, error) {
+	file, err := r.File(id)
+	if err != nil {
+		return nil, err
+	}
+	sr, err := r.OpenStream(file, code, "")
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, sr.Size())
+	if _, err := sr.ReadAt(data, 0); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return Bitmap(data), nil
+}
+
+// loadMFT reads the data runs of the $MFT system file so that file records
+// can be located even when the master file table is fragmented.
+//
+// The first record of the master file table is read from the location
+// given in the volume boot record.
+func (r *Reader) loadMFT() error {
+	file, err := r.File(RecordMFT)
+	if err != nil {
+		return err
+	}
+	data, err := r.OpenStream(file, attrtype.Data, "")
+	if err != nil {
+		return err
+	}
+	r.mft.BaseAddr = 0
+	r.mftr = data
+	r.records = data.Size() / r.mft.RecordSize
+	return nil
+}
+
+// contiguousRecordCount returns the number of file records implied by the
+// data length of $MFT when its data runs are unavailable. The header of
+// record 0 is read from the location in the volume boot record, or from
+// $MFTMirr if that fails. It returns zero if neither can be read.
+func (r *Reader) contiguousRecordCount() int64 {
+	file, err := r.mft.File(r.r, RecordMFT)
+	if err != nil {
+		mirror := r.Mirror()
+		if file, err = mirror.File(r.r, RecordMFT); err != nil {
+			return 0
+		}
+	}
+	attr, ok := file.NamedAttribute(attrtype.Data, "")
+	if !ok || attr.Header.Resident() || attr.Nonresident.DataLength < 0 {
+		return 0
+	}
+	return attr.Nonresident.DataLength / r.mft.RecordSize
+}
+
+// warn records a warning.
+func (r *Reader) warn(err error) {
+	r.warnings = append(r.warnings, err)
+}
+
+// applyVolumePolicies reads the volume information and applies the dirty
+// volume and unsupported version policies of the reader.
+func (r *Reader) applyVolumePolicies() error {
+	if r.dirtyPolicy == Ignore && r.versionPolicy == Ignore {
+		return nil
+	}
+
+	info, err := r.VolumeInfo()
+	if err != nil {
+		return err
+	}
+
+	if info.Dirty() {
+		switch r.dirtyPolicy {
+		case Warn:
+			r.warn(ErrDirtyVolume)
+		case Refuse:
+			return ErrDirtyVolume
+		}
+	}
+
+	if !info.Supported() {
+		switch r.versionPolicy {
+		case Warn:
+			r.warn(ErrUnsupportedVersion)
+		case Refuse:
+			return ErrUnsupportedVersion
+		}
+	}
+
+	return nil
+}
+
 // Reload causes the reader to dismiss its cached data and re-read the file
 // system metadata.
 func reload() {
@@ -3,12 +3,16 @@ package ntfs
 // ObjectIDMinLength is the minimum length of an object ID attribute in bytes.
 const ObjectIDMinLength = 16
 
+// ObjectIDMaxLength is the maximum length of an object ID attribute in bytes.
+const ObjectIDMaxLength = 64
+
 // ObjectID holds object ID attribute data.
 type ObjectID struct {
 	Value         GUID
 	BirthVolumeID GUID
 	BirthObjectID GUID
 	DomainID      GUID
+	length        int // The length of the attribute it was unmarshaled from
 }
 
 // UnmarshalBinary unmarshals the little-endian binary representation
@@ -19,6 +23,10 @@ func (id *ObjectID) UnmarshalBinary(data []byte) error {
 	if len(data) < ObjectIDMinLength {
 		return ErrTruncatedData
 	}
+	id.length = len(data)
+	if id.length > ObjectIDMaxLength {
+		id.length = ObjectIDMaxLength
+	}
 	id.Value = unmarshalGUID(data[0:16])
 	if len(data) >= 32 {
 		id.BirthVolumeID = unmarshalGUID(data[16:32])
@@ -32,6 +40,35 @@ func (id *ObjectID) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// MarshalBinary returns the little-endian binary representation of id.
+//
+// If id was unmarshaled from an attribute the returned data has the same
+// length as that attribute. Otherwise it is 16 bytes long if only the
+// object ID is set, and 64 bytes long if any of the birth or domain IDs
+// are set.
+func (id *ObjectID) MarshalBinary() ([]byte, error) {
+	length := id.length
+	if length == 0 {
+		length = ObjectIDMinLength
+		var zero GUID
+		if id.BirthVolumeID != zero || id.BirthObjectID != zero || id.DomainID != zero {
+			length = ObjectIDMaxLength
+		}
+	}
+	data := make([]byte, length)
+	marshalGUID(data[0:16], id.Value)
+	if length >= 32 {
+		marshalGUID(data[16:32], id.BirthVolumeID)
+	}
+	if length >= 48 {
+		marshalGUID(data[32:48], id.BirthObjectID)
+	}
+	if length >= 64 {
+		marshalGUID(data[48:64], id.DomainID)
+	}
+	return data, nil
+}
+
 // String returns a description of the object ID.
 func (id *ObjectID) String() string {
 	return id.Value.String()
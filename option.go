@@ -0,0 +1,45 @@
+package ntfs
+
+// Policy determines how a Reader responds when it encounters a volume
+// condition that may make it unsafe or unreliable to read.
+type Policy int
+
+// Reader policies.
+const (
+	Ignore Policy = iota // Proceed silently
+	Warn                 // Proceed and record a warning
+	Refuse               // Fail with an error
+)
+
+// String returns a description of the policy.
+func (p Policy) String() string {
+	switch p {
+	case Ignore:
+		return "ignore"
+	case Warn:
+		return "warn"
+	case Refuse:
+		return "refuse"
+	default:
+		return "unknown"
+	}
+}
+
+// Option is a Reader configuration option.
+type Option func(*Reader)
+
+// DirtyVolumePolicy returns an option that determines how a Reader responds
+// to volumes that are marked dirty.
+func DirtyVolumePolicy(p Policy) Option {
+	return func(r *Reader) {
+		r.dirtyPolicy = p
+	}
+}
+
+// UnsupportedVersionPolicy returns an option that determines how a Reader
+// responds to volumes with an NTFS version other than 3.0 or 3.1.
+func UnsupportedVersionPolicy(p Policy) Option {
+	return func(r *Reader) {
+		r.versionPolicy = p
+	}
+}
@@ -53,6 +53,14 @@ func (block *ParameterBlock) ClusterSize() int {
 	return int(block.BytesPerSector) * int(block.SectorsPerCluster)
 }
 
+// Clusters returns the number of clusters in the volume.
+func (block *ParameterBlock) Clusters() int64 {
+	if block.SectorsPerCluster == 0 {
+		return 0
+	}
+	return int64(block.TotalSectors / uint64(block.SectorsPerCluster))
+}
+
 // FileRecordSize returns the size of a file record segment in bytes.
 func (block *ParameterBlock) FileRecordSize() int {
 	if block.ClustersPerFileRecordSegment < 0 {
@@ -69,6 +77,74 @@ func (block *ParameterBlock) IndexBlockSize() int {
 	return int(block.ClustersPerIndexBlock) * block.ClusterSize()
 }
 
+// Validate returns a non-nil error if the parameter block does not describe
+// a plausible NTFS volume geometry.
+func (block *ParameterBlock) Validate() error {
+	switch block.BytesPerSector {
+	case 256, 512, 1024, 2048, 4096:
+	default:
+		return ErrInvalidParameterBlock
+	}
+	if spc := block.SectorsPerCluster; spc == 0 || spc&(spc-1) != 0 {
+		return ErrInvalidParameterBlock
+	}
+	if block.reservedSectors != 0 || block.numberOfFATs != 0 || block.rootDirectoryEntries != 0 || block.totalLogicalSectors != 0 || block.logicalSectorsPerFAT != 0 {
+		return ErrInvalidParameterBlock
+	}
+	if block.ClustersPerFileRecordSegment == 0 || block.ClustersPerIndexBlock == 0 {
+		return ErrInvalidParameterBlock
+	}
+	if block.TotalSectors == 0 {
+		return ErrInvalidParameterBlock
+	}
+	clusters := block.TotalSectors / uint64(block.SectorsPerCluster)
+	if block.MFT >= clusters || block.MFTMirror >= clusters {
+		return ErrInvalidParameterBlock
+	}
+	return nil
+}
+
+// MarshalBinary returns the 73-byte little-endian binary representation of
+// block, including its reserved fields.
+func (block *ParameterBlock) MarshalBinary() ([]byte, error) {
+	data := make([]byte, ParameterBlockLength)
+	block.marshal(data)
+	return data, nil
+}
+
+func (block *ParameterBlock) marshal(data []byte) {
+	// DOS 2.0 parameter block
+	binary.LittleEndian.PutUint16(data[0:2], block.BytesPerSector)
+	data[2] = block.SectorsPerCluster
+	binary.LittleEndian.PutUint16(data[3:5], block.reservedSectors)
+	data[5] = block.numberOfFATs
+	binary.LittleEndian.PutUint16(data[6:8], block.rootDirectoryEntries)
+	binary.LittleEndian.PutUint16(data[8:10], block.totalLogicalSectors)
+	data[10] = uint8(block.MediaDescriptor)
+	binary.LittleEndian.PutUint16(data[11:13], block.logicalSectorsPerFAT)
+
+	// DOS 3.31 parameter block
+	binary.LittleEndian.PutUint16(data[13:15], block.physicalSectorsPerTrack)
+	binary.LittleEndian.PutUint16(data[15:17], block.numberOfHeads)
+	binary.LittleEndian.PutUint32(data[17:21], block.hiddenSectors)
+	binary.LittleEndian.PutUint32(data[21:25], block.totalLogicalSectorsLarge)
+
+	// NTFS extended parameter block
+	data[25] = block.physicalDriveNumber
+	data[26] = block.flags
+	data[27] = block.extendedBootSignature
+	data[28] = block.reserved1
+	binary.LittleEndian.PutUint64(data[29:37], block.TotalSectors)
+	binary.LittleEndian.PutUint64(data[37:45], block.MFT)
+	binary.LittleEndian.PutUint64(data[45:53], block.MFTMirror)
+	data[53] = uint8(block.ClustersPerFileRecordSegment)
+	copy(data[54:57], block.reserved2[:])
+	data[57] = uint8(block.ClustersPerIndexBlock)
+	copy(data[58:61], block.reserved3[:])
+	binary.LittleEndian.PutUint64(data[61:69], block.VolumeSerialNumber)
+	binary.LittleEndian.PutUint32(data[69:73], block.Checksum)
+}
+
 // ReadFrom reads 73 bytes of BIOS parameter block data from r into block.
 func (block *ParameterBlock) ReadFrom(r io.Reader) (n int64, err error) {
 	var buf [73]byte
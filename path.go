@@ -0,0 +1,58 @@
+package ntfs
+
+import (
+	"strings"
+)
+
+// FileByPath retrieves the file at the given path, which is relative to
+// the root directory of the volume. Both forward slashes and backslashes
+// are accepted as separators.
+//
+// Each directory along the path is searched through its file name index.
+// Names are compared case-insensitively.
+func (r *Reader) FileByPath(path string) (*File, error) {
+	file, err := r.File(RecordRoot)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range splitPath(path) {
+		if !file.Header.Directory() {
+			return nil, ErrNotDirectory
+		}
+		entries, err := r.IndexEntries(file, FileNameIndex)
+		if err != nil {
+			return nil, err
+		}
+		var (
+			found bool
+			ref   FileReference
+		)
+		for i := range entries {
+			fn, err := entries[i].FileName()
+			if err != nil {
+				continue
+			}
+			if strings.EqualFold(fn.Value, name) {
+				found, ref = true, entries[i].FileReference
+				break
+			}
+		}
+		if !found {
+			return nil, ErrFileNotFound
+		}
+		if file, err = r.File(ref.SegmentNumber()); err != nil {
+			return nil, err
+		}
+		if file.Header.SequenceNumber != ref.SequenceNumber {
+			return nil, ErrFileNotFound
+		}
+	}
+	return file, nil
+}
+
+// splitPath returns the non-empty components of path.
+func splitPath(path string) []string {
+	return strings.FieldsFunc(path, func(c rune) bool {
+		return c == '/' || c == '\\'
+	})
+}
@@ -0,0 +1,134 @@
+package ntfs
+
+import "strconv"
+
+// OrphanDirectory is the path under which the resolver places files whose
+// parent directory no longer exists.
+const OrphanDirectory = `\$OrphanFiles`
+
+// PathResolver builds full paths for files from the bottom up by following
+// the parent directory references in their file names.
+//
+// The path of each directory is cached after it has been resolved, which
+// makes it suitable for resolving the paths of every file on a volume.
+// Files with a parent that is not in use, is not a directory, has been
+// reused or can't be read are placed under OrphanDirectory instead of
+// causing an error.
+//
+// A PathResolver is not safe for concurrent use.
+type PathResolver struct {
+	r    *Reader
+	dirs map[int64]*resolvedDirectory
+}
+
+// resolvedDirectory is a cached directory path.
+type resolvedDirectory struct {
+	valid     bool   // The record is an in-use directory
+	resolving bool   // The path is being resolved, used for loop detection
+	sequence  uint16 // The sequence number of the record
+	path      string
+}
+
+// NewPathResolver returns a path resolver for the files of r.
+func (r *Reader) NewPathResolver() *PathResolver {
+	return &PathResolver{
+		r:    r,
+		dirs: make(map[int64]*resolvedDirectory),
+	}
+}
+
+// Path returns the full path of file using its preferred name.
+//
+// If file doesn't have a file name ErrNoFileName is returned.
+func (pr *PathResolver) Path(file *File) (string, error) {
+	if file.ID == RecordRoot {
+		return PathSeparator, nil
+	}
+	names, err := pr.r.Names(file)
+	if err != nil {
+		return "", err
+	}
+	name, ok := PreferredName(names)
+	if !ok {
+		return "", ErrNoFileName
+	}
+	return pr.LinkPath(name), nil
+}
+
+// Paths returns the full path of every hard link to file. DOS 8.3 aliases
+// are not included.
+func (pr *PathResolver) Paths(file *File) ([]string, error) {
+	if file.ID == RecordRoot {
+		return []string{PathSeparator}, nil
+	}
+	names, err := pr.r.Names(file)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, name := range names {
+		if name.Flags.Long() {
+			paths = append(paths, pr.LinkPath(name))
+		}
+	}
+	return paths, nil
+}
+
+// LinkPath returns the full path of the hard link described by name.
+func (pr *PathResolver) LinkPath(name FileName) string {
+	dir, _ := pr.Directory(name.ParentDirectory)
+	return joinPath(dir, name.Value)
+}
+
+// Directory returns the full path of the directory identified by ref. If
+// the directory can't be resolved it returns OrphanDirectory and orphan
+// will be true.
+func (pr *PathResolver) Directory(ref FileReference) (path string, orphan bool) {
+	id := ref.SegmentNumber()
+	dir := pr.directory(id)
+	if !dir.valid || dir.resolving || dir.sequence != ref.SequenceNumber {
+		return OrphanDirectory, true
+	}
+	return dir.path, false
+}
+
+// directory returns the cached directory for the record id, resolving it
+// if necessary.
+func (pr *PathResolver) directory(id int64) *resolvedDirectory {
+	if dir, ok := pr.dirs[id]; ok {
+		return dir
+	}
+
+	dir := &resolvedDirectory{resolving: true}
+	pr.dirs[id] = dir
+	defer func() { dir.resolving = false }()
+
+	file, err := pr.r.File(id)
+	if err != nil || !file.Header.InUse() || !file.Header.Directory() {
+		return dir
+	}
+	dir.valid = true
+	dir.sequence = file.Header.SequenceNumber
+
+	if id == RecordRoot {
+		dir.path = PathSeparator
+		return dir
+	}
+	names, err := pr.r.Names(file)
+	if err != nil {
+		dir.path = joinPath(OrphanDirectory, unnamed(id))
+		return dir
+	}
+	name, ok := PreferredName(names)
+	if !ok {
+		dir.path = joinPath(OrphanDirectory, unnamed(id))
+		return dir
+	}
+	dir.path = pr.LinkPath(name)
+	return dir
+}
+
+// unnamed returns a placeholder name for the record id.
+func unnamed(id int64) string {
+	return "$Record" + strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,223 @@
+package ntfs
+
+import (
+	"bytes"
+	"io"
+	"sort"
+)
+
+// scanAlignment is the granularity at which ScanVolumes searches for boot
+// sectors. Volumes always start on a 512-byte boundary.
+const scanAlignment = 512
+
+// scanChunkSize is the amount of data read at a time by ScanVolumes.
+const scanChunkSize = 1 << 20
+
+// Confidence scores awarded to volume candidates for each piece of evidence
+// that supports them. A candidate with every piece of evidence scores 100.
+const (
+	confidencePrimary  = 25 // Valid primary boot sector
+	confidenceBackup   = 25 // Valid backup boot sector
+	confidenceMatch    = 10 // Primary and backup boot sectors are identical
+	confidenceMFT      = 20 // $MFT record 0 found at the location in the boot sector
+	confidenceMirror   = 10 // $MFTMirr record 0 found at the location in the boot sector
+	confidenceContains = 10 // The volume fits within the image
+)
+
+// VolumeCandidate is a possible NTFS volume found by ScanVolumes.
+type VolumeCandidate struct {
+	Offset     int64      // Offset of the first sector of the volume in bytes
+	Length     int64      // Length of the volume in bytes, including the backup boot sector
+	Boot       BootRecord // Taken from the primary boot sector if it is valid
+	Primary    bool       // A valid primary boot sector is present
+	Backup     bool       // A valid backup boot sector is present
+	Match      bool       // The primary and backup boot sectors are identical
+	MFT        bool       // $MFT record 0 is present
+	MFTMirror  bool       // $MFTMirr record 0 is present
+	Truncated  bool       // The volume extends past the end of the image
+	Confidence int        // 0 to 100
+}
+
+// Open returns a reader for the candidate volume within image that can be
+// passed to NewReader. If the candidate is truncated the reader ends at the
+// end of the image.
+func (c VolumeCandidate) Open(image io.ReaderAt) *io.SectionReader {
+	return io.NewSectionReader(image, c.Offset, c.Length)
+}
+
+// ScanVolumes searches the first size bytes of image for NTFS boot sectors
+// and backup boot sectors. It can be used to locate volumes on disks with
+// a damaged or missing partition table.
+//
+// Every 512-byte aligned sector is examined. Each boot sector that is found
+// implies a volume offset: primary boot sectors mark the start of a volume
+// and backup boot sectors mark its end. For each implied offset the primary
+// and backup boot sectors are cross-checked using the TotalSectors of the
+// volume, and the first records of $MFT and $MFTMirr are looked for at the
+// locations given in the boot sector. The resulting evidence determines the
+// confidence score of the candidate. A boot sector that could belong to
+// more than one candidate is attributed to the one with the highest score.
+//
+// Candidates are returned in order of their offset.
+func ScanVolumes(image io.ReaderAt, size int64) ([]VolumeCandidate, error) {
+	// Map each implied volume offset to the offset of the backup boot
+	// sector that implied it, or -1 if it was implied by a primary.
+	backups := make(map[int64]int64)
+	buf := make([]byte, scanChunkSize)
+	for pos := int64(0); pos < size; pos += scanChunkSize {
+		n, err := image.ReadAt(buf, pos)
+		if n < len(buf) && err != nil && err != io.EOF {
+			return nil, err
+		}
+		for i := 0; i+BootSectorLength <= n; i += scanAlignment {
+			data := buf[i : i+BootSectorLength]
+			if !bytes.Equal(data[3:11], Label[:]) {
+				continue
+			}
+			var sector BootSector
+			copy(sector[:], data)
+			if sector.Validate() != nil {
+				continue
+			}
+			boot, _ := sector.Record()
+			offset := pos + int64(i)
+			if _, ok := backups[offset]; !ok {
+				backups[offset] = -1
+			}
+			if start := offset - int64(boot.TotalSectors)*int64(boot.BytesPerSector); start >= 0 {
+				backups[start] = offset
+			}
+		}
+	}
+
+	var candidates []VolumeCandidate
+	for offset, backup := range backups {
+		if c, ok := examineVolume(image, size, offset, backup); ok {
+			candidates = append(candidates, c)
+		}
+	}
+
+	// Every boot sector implies a volume that starts at it and one that
+	// ends at it. Attribute each sector to the candidate with the most
+	// evidence and discard the candidates that are left without one.
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Confidence != candidates[j].Confidence {
+			return candidates[i].Confidence > candidates[j].Confidence
+		}
+		return candidates[i].Offset < candidates[j].Offset
+	})
+	claimed := make(map[int64]bool)
+	filtered := candidates[:0]
+	for _, c := range candidates {
+		backup := c.Offset + c.Length - int64(c.Boot.BytesPerSector)
+		if (c.Primary && claimed[c.Offset]) || (c.Backup && claimed[backup]) {
+			continue
+		}
+		if c.Primary {
+			claimed[c.Offset] = true
+		}
+		if c.Backup {
+			claimed[backup] = true
+		}
+		filtered = append(filtered, c)
+	}
+	candidates = filtered
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Offset < candidates[j].Offset
+	})
+	return candidates, nil
+}
+
+// examineVolume gathers the evidence for a volume starting at offset. If
+// the primary boot sector is invalid, the backup boot sector at backupOffset
+// is used instead when it is non-negative.
+func examineVolume(image io.ReaderAt, size, offset, backupOffset int64) (c VolumeCandidate, ok bool) {
+	c.Offset = offset
+
+	primary, err := readBootSectorAt(image, offset)
+	if err == nil {
+		c.Primary = true
+		c.Boot, _ = primary.Record()
+		backupOffset = offset + int64(c.Boot.TotalSectors)*int64(c.Boot.BytesPerSector)
+	}
+
+	// The backup must describe a volume of the same size
+	if backupOffset >= 0 {
+		if backup, err := readBootSectorAt(image, backupOffset); err == nil {
+			record, _ := backup.Record()
+			if start := backupOffset - int64(record.TotalSectors)*int64(record.BytesPerSector); start == offset {
+				c.Backup = true
+				if c.Primary {
+					c.Match = primary.Equal(&backup)
+				} else {
+					c.Boot = record
+				}
+			}
+		}
+	}
+
+	if !c.Primary && !c.Backup {
+		return c, false
+	}
+
+	c.Length = (int64(c.Boot.TotalSectors) + 1) * int64(c.Boot.BytesPerSector)
+	if c.Offset+c.Length > size {
+		c.Truncated = true
+	}
+
+	volume := io.NewSectionReader(image, c.Offset, c.Length)
+	mft := MFT{
+		SectorSize:  int64(c.Boot.BytesPerSector),
+		ClusterSize: int64(c.Boot.ClusterSize()),
+		RecordSize:  int64(c.Boot.FileRecordSize()),
+		BaseAddr:    int64(c.Boot.MFT) * int64(c.Boot.ClusterSize()),
+	}
+	c.MFT = hasMFTRecord(volume, mft)
+	mft.BaseAddr = int64(c.Boot.MFTMirror) * int64(c.Boot.ClusterSize())
+	c.MFTMirror = hasMFTRecord(volume, mft)
+
+	if c.Primary {
+		c.Confidence += confidencePrimary
+	}
+	if c.Backup {
+		c.Confidence += confidenceBackup
+	}
+	if c.Match {
+		c.Confidence += confidenceMatch
+	}
+	if c.MFT {
+		c.Confidence += confidenceMFT
+	}
+	if c.MFTMirror {
+		c.Confidence += confidenceMirror
+	}
+	if !c.Truncated {
+		c.Confidence += confidenceContains
+	}
+
+	return c, true
+}
+
+// readBootSectorAt reads and validates a boot sector at offset.
+func readBootSectorAt(image io.ReaderAt, offset int64) (sector BootSector, err error) {
+	if _, err = sector.ReadFrom(io.NewSectionReader(image, offset, BootSectorLength)); err != nil {
+		return sector, err
+	}
+	return sector, sector.Validate()
+}
+
+// hasMFTRecord returns true if the first record of mft can be read from
+// volume and names the $MFT system file.
+func hasMFTRecord(volume io.ReadSeeker, mft MFT) bool {
+	file, err := mft.File(volume, 0)
+	if err != nil {
+		return false
+	}
+	names, err := file.Names()
+	if err != nil {
+		return false
+	}
+	name, ok := PreferredName(names)
+	return ok && name.Value == "$MFT"
+}
@@ -0,0 +1,201 @@
+package ntfs
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gentlemanautomaton/ntfs/attrtype"
+)
+
+// https://flatcap.org/linux-ntfs/ntfs/files/secure.html
+// https://docs.microsoft.com/windows/win32/api/winnt/ns-winnt-security_descriptor
+
+// SecurityStream is the name of the $DATA stream of the $Secure system file
+// that holds the security descriptors of the volume.
+const SecurityStream = "$SDS"
+
+// SecurityDescriptorHeaderLength is the length of the header that precedes
+// each security descriptor in the $SDS stream in bytes.
+const SecurityDescriptorHeaderLength = 20
+
+// SecurityDescriptorMinLength is the minimum length of a self-relative
+// security descriptor in bytes.
+const SecurityDescriptorMinLength = 20
+
+// sdsBlockSize is the size of the blocks of the $SDS stream. Each block is
+// followed by a mirror copy of itself.
+const sdsBlockSize = 256 * 1024
+
+// SecurityDescriptorEntry is a security descriptor stored in the $SDS
+// stream of the $Secure system file. Files refer to it by its security ID.
+type SecurityDescriptorEntry struct {
+	Hash       uint32
+	SecurityID uint32
+	Offset     uint64 // The offset of the entry within the $SDS stream
+	Length     uint32 // The length of the entry, including its header
+	Descriptor SecurityDescriptor
+}
+
+// SecurityDescriptor is a self-relative security descriptor.
+type SecurityDescriptor []byte
+
+// Control returns the control flags of the security descriptor.
+func (sd SecurityDescriptor) Control() uint16 {
+	if len(sd) < SecurityDescriptorMinLength {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(sd[2:4])
+}
+
+// Owner returns the owner SID of the security descriptor. If the security
+// descriptor doesn't have an owner ok will be false.
+func (sd SecurityDescriptor) Owner() (sid SID, ok bool) {
+	return sd.sid(4)
+}
+
+// Group returns the primary group SID of the security descriptor. If the
+// security descriptor doesn't have a group ok will be false.
+func (sd SecurityDescriptor) Group() (sid SID, ok bool) {
+	return sd.sid(8)
+}
+
+// sid returns the SID referred to by the offset at pos.
+func (sd SecurityDescriptor) sid(pos int) (sid SID, ok bool) {
+	if len(sd) < SecurityDescriptorMinLength {
+		return nil, false
+	}
+	offset := int(binary.LittleEndian.Uint32(sd[pos : pos+4]))
+	if offset == 0 || offset >= len(sd) {
+		return nil, false
+	}
+	sid = SID(sd[offset:])
+	if !sid.valid() {
+		return nil, false
+	}
+	return sid[:sid.length()], true
+}
+
+// SID is a binary security identifier.
+type SID []byte
+
+// valid returns true if sid holds a complete security identifier.
+func (sid SID) valid() bool {
+	return len(sid) >= 8 && len(sid) >= sid.length()
+}
+
+// length returns the length of sid in bytes.
+func (sid SID) length() int {
+	return 8 + 4*int(sid[1])
+}
+
+// String returns the string representation of sid, such as "S-1-5-18".
+func (sid SID) String() string {
+	if !sid.valid() {
+		return ""
+	}
+	var authority uint64
+	for _, b := range sid[2:8] {
+		authority = authority<<8 | uint64(b)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "S-%d-%d", sid[0], authority)
+	for i := 0; i < int(sid[1]); i++ {
+		b.WriteString("-")
+		b.WriteString(strconv.FormatUint(uint64(binary.LittleEndian.Uint32(sid[8+i*4:])), 10))
+	}
+	return b.String()
+}
+
+// UnmarshalBinary unmarshals the little-endian binary representation of a
+// $SDS entry into entry. The security descriptor is copied.
+func (entry *SecurityDescriptorEntry) UnmarshalBinary(data []byte) error {
+	if len(data) < SecurityDescriptorHeaderLength {
+		return ErrTruncatedData
+	}
+	entry.Hash = binary.LittleEndian.Uint32(data[0:4])
+	entry.SecurityID = binary.LittleEndian.Uint32(data[4:8])
+	entry.Offset = binary.LittleEndian.Uint64(data[8:16])
+	entry.Length = binary.LittleEndian.Uint32(data[16:20])
+	if entry.Length < SecurityDescriptorHeaderLength || int(entry.Length) > len(data) {
+		return ErrTruncatedData
+	}
+	entry.Descriptor = append(SecurityDescriptor(nil), data[SecurityDescriptorHeaderLength:entry.Length]...)
+	return nil
+}
+
+// SecurityDescriptors calls fn for each security descriptor stored in the
+// $Secure system file, in the order they are stored. The mirror copies of
+// the descriptors are skipped.
+//
+// If fn returns an error iteration stops and the error is returned.
+func (r *Reader) SecurityDescriptors(fn func(SecurityDescriptorEntry) error) error {
+	file, err := r.File(RecordSecure)
+	if err != nil {
+		return err
+	}
+	stream, err := r.OpenStream(file, attrtype.Data, SecurityStream)
+	if err != nil {
+		return err
+	}
+
+	// Each block is followed by its mirror, so only even blocks are read
+	for block := int64(0); block*sdsBlockSize < stream.Size(); block += 2 {
+		length := int64(sdsBlockSize)
+		if remaining := stream.Size() - block*sdsBlockSize; remaining < length {
+			length = remaining
+		}
+		br := bufio.NewReader(io.NewSectionReader(stream, block*sdsBlockSize, length))
+		if err := readSecurityDescriptors(br, int(length), fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readSecurityDescriptors reads the $SDS entries of a block of size bytes
+// from br.
+func readSecurityDescriptors(br *bufio.Reader, size int, fn func(SecurityDescriptorEntry) error) error {
+	var pos int
+	for {
+		header, err := br.Peek(SecurityDescriptorHeaderLength)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		length := int(binary.LittleEndian.Uint32(header[16:20]))
+		if length < SecurityDescriptorHeaderLength {
+			// The remainder of the block is unused
+			return nil
+		}
+		if length > size-pos {
+			// The entry is corrupt and would overrun the block
+			return nil
+		}
+		data := make([]byte, length)
+		if _, err := io.ReadFull(br, data); err != nil {
+			if err == io.ErrUnexpectedEOF {
+				return nil
+			}
+			return err
+		}
+		var entry SecurityDescriptorEntry
+		if err := entry.UnmarshalBinary(data); err == nil {
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+
+		// Entries are aligned on 16-byte boundaries
+		pos += length
+		if pad := (16 - pos%16) % 16; pad > 0 {
+			n, _ := br.Discard(pad)
+			pos += n
+		}
+	}
+}
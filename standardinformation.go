@@ -4,12 +4,18 @@ import (
 	"encoding/binary"
 	"fmt"
 	"time"
+
+	"github.com/gentlemanautomaton/ntfs/fileattr"
 )
 
 // StandardInformationMinLength is the minimum length of a standard
 // information attribute in bytes.
 const StandardInformationMinLength = 48
 
+// StandardInformationLength is the length of a standard information
+// attribute in bytes on NTFS 3.0 and later volumes.
+const StandardInformationLength = 72
+
 // StandardInformation holds standard attribute data.
 //
 // https://msdn.microsoft.com/library/bb545266
@@ -18,7 +24,7 @@ type StandardInformation struct {
 	FileModification   time.Time
 	MFTModification    time.Time
 	FileRead           time.Time
-	DOSFilePermissions uint32
+	DOSFilePermissions fileattr.Flag
 	MaxVersions        uint32
 	VersionNumber      uint32
 	ClassID            uint32
@@ -26,6 +32,7 @@ type StandardInformation struct {
 	SecurityID         uint32
 	QuotaCharged       uint64
 	USN                uint64
+	short              bool // Unmarshaled from a 48-byte attribute that lacks the NTFS 3.0 fields
 }
 
 // UnmarshalBinary unmarshals the little-endian binary representation
@@ -40,11 +47,12 @@ func (info *StandardInformation) UnmarshalBinary(data []byte) error {
 	info.FileModification = unmarshalFileTime(data[8:16])
 	info.MFTModification = unmarshalFileTime(data[16:24])
 	info.FileRead = unmarshalFileTime(data[24:32])
-	info.DOSFilePermissions = binary.LittleEndian.Uint32(data[32:36])
+	info.DOSFilePermissions = fileattr.Unmarshal(data[32:36])
 	info.MaxVersions = binary.LittleEndian.Uint32(data[36:40])
 	info.VersionNumber = binary.LittleEndian.Uint32(data[40:44])
 	info.ClassID = binary.LittleEndian.Uint32(data[44:48])
-	if len(data) >= 72 {
+	info.short = len(data) < StandardInformationLength
+	if !info.short {
 		info.OwnerID = binary.LittleEndian.Uint32(data[48:52])
 		info.SecurityID = binary.LittleEndian.Uint32(data[52:56])
 		info.QuotaCharged = binary.LittleEndian.Uint64(data[56:64])
@@ -53,6 +61,33 @@ func (info *StandardInformation) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// MarshalBinary returns the little-endian binary representation of info.
+//
+// The returned data is 72 bytes long unless info was unmarshaled from a
+// 48-byte attribute, in which case it is 48 bytes long.
+func (info *StandardInformation) MarshalBinary() ([]byte, error) {
+	length := StandardInformationLength
+	if info.short {
+		length = StandardInformationMinLength
+	}
+	data := make([]byte, length)
+	marshalFileTime(data[0:8], info.FileCreation)
+	marshalFileTime(data[8:16], info.FileModification)
+	marshalFileTime(data[16:24], info.MFTModification)
+	marshalFileTime(data[24:32], info.FileRead)
+	binary.LittleEndian.PutUint32(data[32:36], uint32(info.DOSFilePermissions))
+	binary.LittleEndian.PutUint32(data[36:40], info.MaxVersions)
+	binary.LittleEndian.PutUint32(data[40:44], info.VersionNumber)
+	binary.LittleEndian.PutUint32(data[44:48], info.ClassID)
+	if !info.short {
+		binary.LittleEndian.PutUint32(data[48:52], info.OwnerID)
+		binary.LittleEndian.PutUint32(data[52:56], info.SecurityID)
+		binary.LittleEndian.PutUint64(data[56:64], info.QuotaCharged)
+		binary.LittleEndian.PutUint64(data[64:72], info.USN)
+	}
+	return data, nil
+}
+
 // String returns a description of the standard information.
 func (info *StandardInformation) String() string {
 	c := localTimeString(info.FileCreation)
@@ -0,0 +1,89 @@
+package ntfs
+
+import (
+	"github.com/gentlemanautomaton/ntfs/attrflag"
+	"github.com/gentlemanautomaton/ntfs/attrtype"
+)
+
+// Stream describes a $DATA stream of a file.
+type Stream struct {
+	Name            string // Empty for the default stream
+	Instance        uint16 // The attribute instance of the first segment
+	Flags           attrflag.Flag
+	Resident        bool
+	Size            int64 // Bytes of actual data
+	AllocatedSize   int64 // Bytes allocated on disk
+	InitializedSize int64
+	CompressedSize  int64 // Bytes allocated on disk for compressed or sparse streams
+	Segments        int   // The number of attribute segments holding the stream
+}
+
+// Streams returns the $DATA streams of file, including those stored in
+// extension records.
+func (r *Reader) Streams(file *File) ([]Stream, error) {
+	entries, err := r.AttributeList(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	add := func(name string) {
+		for _, existing := range names {
+			if existing == name {
+				return
+			}
+		}
+		names = append(names, name)
+	}
+	if entries != nil {
+		for _, entry := range entries {
+			if entry.TypeCode == attrtype.Data {
+				add(entry.AttributeName)
+			}
+		}
+	} else {
+		for a := range file.Attributes {
+			if file.Attributes[a].Header.TypeCode == attrtype.Data {
+				add(file.Attributes[a].Name)
+			}
+		}
+	}
+
+	streams := make([]Stream, 0, len(names))
+	for _, name := range names {
+		segments, err := r.Segments(file, attrtype.Data, name)
+		if err != nil {
+			return nil, err
+		}
+		first := &segments[0]
+		stream := Stream{
+			Name:     name,
+			Instance: first.Header.Instance,
+			Flags:    first.Header.Flags,
+			Resident: first.Header.Resident(),
+			Segments: len(segments),
+		}
+		if stream.Resident {
+			stream.Size = int64(len(first.ResidentValue))
+			stream.AllocatedSize = stream.Size
+			stream.InitializedSize = stream.Size
+		} else {
+			stream.Size = first.Nonresident.DataLength
+			stream.AllocatedSize = first.Nonresident.AllocatedLength
+			stream.InitializedSize = first.Nonresident.InitializedLength
+			stream.CompressedSize = first.Nonresident.CompressedLength
+
+			// Sparse attributes don't always record their compressed
+			// length, so count the clusters that back them instead
+			if stream.CompressedSize == 0 && stream.Flags&(attrflag.CompressionMask|attrflag.Sparse) != 0 && !r.Standalone() {
+				runlist, err := segmentRunlist(segments)
+				if err != nil {
+					return nil, err
+				}
+				stream.CompressedSize = int64(runlist.Allocated()) * int64(r.boot.ClusterSize())
+			}
+		}
+		streams = append(streams, stream)
+	}
+	return streams, nil
+}
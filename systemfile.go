@@ -0,0 +1,19 @@
+package ntfs
+
+// https://flatcap.org/linux-ntfs/ntfs/files/index.html
+
+// Well-known file record numbers of the NTFS system files.
+const (
+	RecordMFT     = 0  // $MFT
+	RecordMFTMirr = 1  // $MFTMirr
+	RecordLogFile = 2  // $LogFile
+	RecordVolume  = 3  // $Volume
+	RecordAttrDef = 4  // $AttrDef
+	RecordRoot    = 5  // . (the root directory)
+	RecordBitmap  = 6  // $Bitmap
+	RecordBoot    = 7  // $Boot
+	RecordBadClus = 8  // $BadClus
+	RecordSecure  = 9  // $Secure
+	RecordUpCase  = 10 // $UpCase
+	RecordExtend  = 11 // $Extend
+)
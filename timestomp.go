@@ -0,0 +1,194 @@
+package ntfs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gentlemanautomaton/ntfs/attrtype"
+)
+
+// TimestampAnomalyKind identifies a kind of timestamp anomaly.
+type TimestampAnomalyKind string
+
+// Kinds of timestamp anomalies. Each is a common signature of timestamps
+// that have been manipulated, although each can also occur legitimately.
+const (
+	CreationBeforeFileName TimestampAnomalyKind = "creation-before-file-name" // $STANDARD_INFORMATION creation precedes $FILE_NAME creation
+	ZeroFraction           TimestampAnomalyKind = "zero-fraction"             // A timestamp has no sub-second precision
+	ModifiedBeforeCreated  TimestampAnomalyKind = "modified-before-created"   // A modification time precedes the creation time
+	FutureTimestamp        TimestampAnomalyKind = "future-timestamp"          // A timestamp is later than the reference time
+)
+
+// futureTolerance is the amount of time a timestamp may be later than the
+// reference time without being reported.
+const futureTolerance = time.Minute
+
+// TimestampAnomaly describes a suspicious timestamp.
+type TimestampAnomaly struct {
+	Kind      TimestampAnomalyKind `json:"kind"`
+	Attribute string               `json:"attribute"` // $STANDARD_INFORMATION or $FILE_NAME
+	Field     string               `json:"field"`     // creation, modification, mft-modification or read
+	Time      time.Time            `json:"time"`
+	Detail    string               `json:"detail,omitempty"`
+}
+
+// String returns a description of the anomaly.
+func (a TimestampAnomaly) String() string {
+	output := fmt.Sprintf("%s %s %s %s", a.Kind, a.Attribute, a.Field, a.Time.UTC().Format(time.RFC3339Nano))
+	if a.Detail != "" {
+		output += ": " + a.Detail
+	}
+	return output
+}
+
+// TimestampFinding lists the timestamp anomalies of a file.
+type TimestampFinding struct {
+	Record    int64              `json:"record"`
+	Sequence  uint16             `json:"sequence"`
+	Path      string             `json:"path"`
+	Anomalies []TimestampAnomaly `json:"anomalies"`
+}
+
+// fileTimes holds the four timestamps of a $STANDARD_INFORMATION or
+// $FILE_NAME attribute.
+type fileTimes struct {
+	attribute    attrtype.Code
+	creation     time.Time
+	modification time.Time
+	mft          time.Time
+	read         time.Time
+}
+
+// fileTimeField is a timestamp with its field name.
+type fileTimeField struct {
+	name string
+	t    time.Time
+}
+
+// fields returns the timestamps of ft with their field names.
+func (ft *fileTimes) fields() [4]fileTimeField {
+	return [4]fileTimeField{
+		{"creation", ft.creation},
+		{"modification", ft.modification},
+		{"mft-modification", ft.mft},
+		{"read", ft.read},
+	}
+}
+
+// AnalyzeTimestamps compares the timestamps of a file's standard
+// information and file names and returns any anomalies found.
+//
+// Timestamps later than reference are reported as future timestamps. If
+// reference is zero future timestamps are not reported.
+func AnalyzeTimestamps(si *StandardInformation, names []FileName, reference time.Time) []TimestampAnomaly {
+	var (
+		anomalies []TimestampAnomaly
+		sets      []fileTimes
+	)
+	if si != nil {
+		sets = append(sets, fileTimes{attrtype.StandardInformation, si.FileCreation, si.FileModification, si.MFTModification, si.FileRead})
+	}
+	for i := range names {
+		if !names[i].Flags.Long() {
+			continue
+		}
+		fn := &names[i]
+		sets = append(sets, fileTimes{attrtype.FileName, fn.FileCreation, fn.FileModification, fn.MFTModification, fn.FileRead})
+		if si != nil && si.FileCreation.Before(fn.FileCreation) {
+			anomalies = append(anomalies, TimestampAnomaly{
+				Kind:      CreationBeforeFileName,
+				Attribute: attrtype.StandardInformation.String(),
+				Field:     "creation",
+				Time:      si.FileCreation,
+				Detail:    fmt.Sprintf("%q was created %s", fn.Value, fn.FileCreation.UTC().Format(time.RFC3339Nano)),
+			})
+		}
+	}
+
+	for i := range sets {
+		set := &sets[i]
+		attribute := set.attribute.String()
+		if set.modification.Before(set.creation) {
+			anomalies = append(anomalies, TimestampAnomaly{
+				Kind:      ModifiedBeforeCreated,
+				Attribute: attribute,
+				Field:     "modification",
+				Time:      set.modification,
+				Detail:    fmt.Sprintf("created %s", set.creation.UTC().Format(time.RFC3339Nano)),
+			})
+		}
+		for _, field := range set.fields() {
+			if field.t.IsZero() {
+				continue
+			}
+			if field.t.Nanosecond() == 0 {
+				anomalies = append(anomalies, TimestampAnomaly{
+					Kind:      ZeroFraction,
+					Attribute: attribute,
+					Field:     field.name,
+					Time:      field.t,
+				})
+			}
+			if !reference.IsZero() && field.t.After(reference.Add(futureTolerance)) {
+				anomalies = append(anomalies, TimestampAnomaly{
+					Kind:      FutureTimestamp,
+					Attribute: attribute,
+					Field:     field.name,
+					Time:      field.t,
+					Detail:    fmt.Sprintf("reference time %s", reference.UTC().Format(time.RFC3339Nano)),
+				})
+			}
+		}
+	}
+	return anomalies
+}
+
+// AnalyzeTimestamps analyzes the timestamps of every in-use file on the
+// volume and calls fn for each file with anomalies.
+//
+// Timestamps later than reference are reported as future timestamps. If
+// reference is zero the newest timestamp in the USN change journal is used
+// instead. $LogFile records don't carry timestamps, so if the volume
+// doesn't have a journal future timestamps are not reported.
+//
+// If fn returns an error the analysis stops and the error is returned.
+func (r *Reader) AnalyzeTimestamps(reference time.Time, fn func(TimestampFinding) error) error {
+	if reference.IsZero() {
+		latest, err := r.LatestUSNTime()
+		if err != nil && err != ErrNoUSNJournal {
+			return err
+		}
+		reference = latest
+	}
+
+	resolver := r.NewPathResolver()
+	return r.Walk(func(file *File, err error) error {
+		if err != nil || !file.Header.InUse() || !file.Header.BaseFileRecordSegment.IsZero() {
+			return nil
+		}
+
+		var si *StandardInformation
+		if attr, ok := file.Attribute(attrtype.StandardInformation); ok {
+			si = new(StandardInformation)
+			if err := si.UnmarshalBinary(attr.ResidentValue); err != nil {
+				si = nil
+			}
+		}
+		names, err := r.Names(file)
+		if err != nil {
+			names = nil
+		}
+
+		anomalies := AnalyzeTimestamps(si, names, reference)
+		if len(anomalies) == 0 {
+			return nil
+		}
+		path, _ := resolver.Path(file)
+		return fn(TimestampFinding{
+			Record:    file.ID,
+			Sequence:  file.Header.SequenceNumber,
+			Path:      path,
+			Anomalies: anomalies,
+		})
+	})
+}
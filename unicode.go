@@ -1,6 +1,15 @@
 package ntfs
 
-import "unicode/utf16"
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// NTFS names are sequences of 16-bit code units that aren't required to be
+// valid UTF-16. Unpaired surrogates are kept in the returned strings using
+// their 3-byte generalized UTF-8 encoding (WTF-8) so that names can be
+// written back exactly as they were read.
+// https://simonsapin.github.io/wtf-8/
 
 // utf16ToString converts data from a unicode 16 byte sequence to a string.
 //
@@ -17,5 +26,58 @@ func utf16ToString(data []byte) (string, error) {
 	for i := 0; i < length; i++ {
 		buf[i] = uint16(data[i*2]) | uint16(data[i*2+1])<<8
 	}
-	return string(utf16.Decode(buf[:length])), nil
+	out := make([]byte, 0, length*3)
+	for i := 0; i < length; i++ {
+		c := buf[i]
+		switch {
+		case utf16.IsSurrogate(rune(c)):
+			if i+1 < length {
+				if r := utf16.DecodeRune(rune(c), rune(buf[i+1])); r != utf8.RuneError {
+					out = utf8.AppendRune(out, r)
+					i++
+					continue
+				}
+			}
+			// Unpaired surrogate
+			out = append(out, 0xE0|byte(c>>12), 0x80|byte(c>>6)&0x3F, 0x80|byte(c)&0x3F)
+		default:
+			out = utf8.AppendRune(out, rune(c))
+		}
+	}
+	return string(out), nil
+}
+
+// stringToUTF16 converts s to a little-endian unicode 16 byte sequence.
+//
+// Unpaired surrogates encoded by utf16ToString are restored. Other invalid
+// UTF-8 sequences are replaced by U+FFFD.
+func stringToUTF16(s string) []byte {
+	var buf []uint16
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			if c, ok := decodeSurrogate(s[i:]); ok {
+				buf = append(buf, c)
+				i += 3
+				continue
+			}
+		}
+		buf = utf16.AppendRune(buf, r)
+		i += size
+	}
+	data := make([]byte, len(buf)*2)
+	for i, c := range buf {
+		data[i*2] = byte(c)
+		data[i*2+1] = byte(c >> 8)
+	}
+	return data
+}
+
+// decodeSurrogate decodes a surrogate code point from the generalized UTF-8
+// sequence at the start of s.
+func decodeSurrogate(s string) (c uint16, ok bool) {
+	if len(s) < 3 || s[0] != 0xED || s[1] < 0xA0 || s[1] > 0xBF || s[2] < 0x80 || s[2] > 0xBF {
+		return 0, false
+	}
+	return 0xD000 | uint16(s[1]&0x3F)<<6 | uint16(s[2]&0x3F), true
 }
@@ -0,0 +1,219 @@
+package ntfs
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/gentlemanautomaton/ntfs/attrtype"
+	"github.com/gentlemanautomaton/ntfs/fileattr"
+	"github.com/gentlemanautomaton/ntfs/usnreason"
+)
+
+// https://docs.microsoft.com/windows/win32/api/winioctl/ns-winioctl-usn_record_v2
+// https://docs.microsoft.com/windows/win32/api/winioctl/ns-winioctl-usn_record_v3
+
+// USNJournalPath is the path of the file that holds the USN change journal.
+const USNJournalPath = `\$Extend\$UsnJrnl`
+
+// USNJournalStream is the name of the $DATA stream of the USN change
+// journal that holds its records.
+const USNJournalStream = "$J"
+
+// USNRecordV2MinLength is the minimum length of a version 2 USN record in
+// bytes.
+const USNRecordV2MinLength = 60
+
+// USNRecordV3MinLength is the minimum length of a version 3 USN record in
+// bytes.
+const USNRecordV3MinLength = 76
+
+// usnPageSize is the size of the pages that USN records are written in.
+// Records do not cross page boundaries, and the unused space at the end
+// of each page is filled with zeros.
+const usnPageSize = 4096
+
+// USNRecord is a record of the USN change journal.
+//
+// Version 3 records hold 128-bit file references. Only the low 64 bits of
+// each, which is all that NTFS uses, are kept.
+type USNRecord struct {
+	MajorVersion       uint16
+	MinorVersion       uint16
+	File               FileReference
+	Parent             FileReference
+	USN                int64
+	Timestamp          time.Time
+	Reason             usnreason.Flag
+	SourceInfo         uint32
+	SecurityID         uint32
+	DOSFilePermissions fileattr.Flag
+	FileName           string
+}
+
+// UnmarshalBinary unmarshals the little-endian binary representation
+// of a version 2 or version 3 USN record into record.
+func (record *USNRecord) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return ErrTruncatedData
+	}
+	length := int(binary.LittleEndian.Uint32(data[0:4]))
+	if length > len(data) {
+		return ErrTruncatedData
+	}
+	data = data[:length]
+	record.MajorVersion = binary.LittleEndian.Uint16(data[4:6])
+	record.MinorVersion = binary.LittleEndian.Uint16(data[6:8])
+
+	var fixed []byte
+	switch record.MajorVersion {
+	case 2:
+		if length < USNRecordV2MinLength {
+			return ErrTruncatedData
+		}
+		if err := record.File.UnmarshalBinary(data[8:16]); err != nil {
+			return err
+		}
+		if err := record.Parent.UnmarshalBinary(data[16:24]); err != nil {
+			return err
+		}
+		fixed = data[24:]
+	case 3:
+		if length < USNRecordV3MinLength {
+			return ErrTruncatedData
+		}
+		if err := record.File.UnmarshalBinary(data[8:16]); err != nil {
+			return err
+		}
+		if err := record.Parent.UnmarshalBinary(data[24:32]); err != nil {
+			return err
+		}
+		fixed = data[40:]
+	default:
+		return ErrUSNRecordVersionUnsupported
+	}
+
+	record.USN = int64(binary.LittleEndian.Uint64(fixed[0:8]))
+	record.Timestamp = unmarshalFileTime(fixed[8:16])
+	record.Reason = usnreason.Unmarshal(fixed[16:20])
+	record.SourceInfo = binary.LittleEndian.Uint32(fixed[20:24])
+	record.SecurityID = binary.LittleEndian.Uint32(fixed[24:28])
+	record.DOSFilePermissions = fileattr.Unmarshal(fixed[28:32])
+	nameLength := int(binary.LittleEndian.Uint16(fixed[32:34]))
+	nameOffset := int(binary.LittleEndian.Uint16(fixed[34:36]))
+	if nameOffset+nameLength > length {
+		return ErrFileNameOutOfBounds
+	}
+	var err error
+	record.FileName, err = utf16ToString(data[nameOffset : nameOffset+nameLength])
+	return err
+}
+
+// USNJournal calls fn for each record of the volume's USN change journal,
+// in the order they are stored.
+//
+// Records that can't be parsed are skipped. If the volume doesn't have a
+// USN change journal ErrNoUSNJournal is returned. If fn returns an error
+// iteration stops and the error is returned.
+func (r *Reader) USNJournal(fn func(USNRecord) error) error {
+	file, err := r.FileByPath(USNJournalPath)
+	if err == ErrFileNotFound {
+		return ErrNoUSNJournal
+	}
+	if err != nil {
+		return err
+	}
+	stream, err := r.OpenStream(file, attrtype.Data, USNJournalStream)
+	if err != nil {
+		return err
+	}
+	extents, err := r.Extents(file, attrtype.Data, USNJournalStream)
+	if err != nil {
+		return err
+	}
+
+	// Most of the stream is sparse, so reading starts at the first
+	// allocated extent
+	for _, e := range extents {
+		if e.Sparse {
+			continue
+		}
+		if e.Offset >= stream.Size() {
+			break
+		}
+		return readUSNRecords(stream, e.Offset, stream.Size(), fn)
+	}
+	return nil
+}
+
+// ReadUSNJournal calls fn for each record of a standalone copy of the $J
+// stream of a USN change journal, such as one collected by a triage tool.
+// The stream is read from rs up to size bytes. Leading zeros, which
+// stand in for the sparse start of the stream, are skipped.
+func ReadUSNJournal(rs io.ReaderAt, size int64, fn func(USNRecord) error) error {
+	return readUSNRecords(rs, 0, size, fn)
+}
+
+// LatestUSNTime returns the newest timestamp in the volume's USN change
+// journal. If the journal has no records a zero time is returned.
+func (r *Reader) LatestUSNTime() (time.Time, error) {
+	var latest time.Time
+	err := r.USNJournal(func(record USNRecord) error {
+		if record.Timestamp.After(latest) {
+			latest = record.Timestamp
+		}
+		return nil
+	})
+	return latest, err
+}
+
+// readUSNRecords reads the USN records of a $J stream from ra, starting
+// at offset start and ending at offset end. Page boundaries are relative
+// to the start of the stream.
+func readUSNRecords(ra io.ReaderAt, start, end int64, fn func(USNRecord) error) error {
+	var (
+		br  = bufio.NewReaderSize(io.NewSectionReader(ra, start, end-start), usnPageSize*16)
+		pos = start
+	)
+	for {
+		header, err := br.Peek(8)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		length := int64(binary.LittleEndian.Uint32(header[0:4]))
+		remaining := usnPageSize - pos%usnPageSize
+		if length == 0 || length%8 != 0 || length > remaining {
+			// Skip the zero padding at the end of the page
+			n, err := br.Discard(int(remaining))
+			pos += int64(n)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		data, err := br.Peek(int(length))
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var record USNRecord
+		if err := record.UnmarshalBinary(data); err == nil {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+		if _, err := br.Discard(int(length)); err != nil {
+			return err
+		}
+		pos += length
+	}
+}
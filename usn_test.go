@@ -0,0 +1,51 @@
+package ntfs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// putUSNRecord writes a version 2 USN record with the given USN and file
+// name at the start of data and returns its length.
+func putUSNRecord(data []byte, usn int64, name string) int {
+	utf16 := stringToUTF16(name)
+	length := (USNRecordV2MinLength + len(utf16) + 7) &^ 7
+	binary.LittleEndian.PutUint32(data[0:4], uint32(length))
+	binary.LittleEndian.PutUint16(data[4:6], 2)
+	binary.LittleEndian.PutUint64(data[24:32], uint64(usn))
+	binary.LittleEndian.PutUint16(data[56:58], uint16(len(utf16)))
+	binary.LittleEndian.PutUint16(data[58:60], USNRecordV2MinLength)
+	copy(data[USNRecordV2MinLength:], utf16)
+	return length
+}
+
+func TestReadUSNRecords(t *testing.T) {
+	// The stream starts with a sparse page, and the first allocated data
+	// starts part way through the second page. The last record in the
+	// third page fills it exactly.
+	data := make([]byte, usnPageSize*3)
+	putUSNRecord(data[0x1800:], 0x1800, "a.txt")
+	n := putUSNRecord(data[0x2000:], 0x2000, "b.txt")
+	last := 0x2000 + n
+	putUSNRecord(data[last:], int64(last), "c.txt")
+	binary.LittleEndian.PutUint32(data[last:], uint32(len(data)-last))
+
+	var got []int64
+	err := readUSNRecords(bytes.NewReader(data), 0x1800, int64(len(data)), func(record USNRecord) error {
+		got = append(got, record.USN)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int64{0x1800, 0x2000, int64(last)}
+	if len(got) != len(want) {
+		t.Fatalf("got records %x, want %x", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("record %d: got USN %#x, want %#x", i, got[i], want[i])
+		}
+	}
+}
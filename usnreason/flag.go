@@ -0,0 +1,148 @@
+package usnreason
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// https://docs.microsoft.com/windows/win32/api/winioctl/ns-winioctl-usn_record_v2
+
+// Flag is a USN journal change reason flag.
+type Flag uint32
+
+// USN journal change reason flags.
+const (
+	DataOverwrite             Flag = 0x00000001 // USN_REASON_DATA_OVERWRITE
+	DataExtend                Flag = 0x00000002 // USN_REASON_DATA_EXTEND
+	DataTruncation            Flag = 0x00000004 // USN_REASON_DATA_TRUNCATION
+	NamedDataOverwrite        Flag = 0x00000010 // USN_REASON_NAMED_DATA_OVERWRITE
+	NamedDataExtend           Flag = 0x00000020 // USN_REASON_NAMED_DATA_EXTEND
+	NamedDataTruncation       Flag = 0x00000040 // USN_REASON_NAMED_DATA_TRUNCATION
+	FileCreate                Flag = 0x00000100 // USN_REASON_FILE_CREATE
+	FileDelete                Flag = 0x00000200 // USN_REASON_FILE_DELETE
+	EAChange                  Flag = 0x00000400 // USN_REASON_EA_CHANGE
+	SecurityChange            Flag = 0x00000800 // USN_REASON_SECURITY_CHANGE
+	RenameOldName             Flag = 0x00001000 // USN_REASON_RENAME_OLD_NAME
+	RenameNewName             Flag = 0x00002000 // USN_REASON_RENAME_NEW_NAME
+	IndexableChange           Flag = 0x00004000 // USN_REASON_INDEXABLE_CHANGE
+	BasicInfoChange           Flag = 0x00008000 // USN_REASON_BASIC_INFO_CHANGE
+	HardLinkChange            Flag = 0x00010000 // USN_REASON_HARD_LINK_CHANGE
+	CompressionChange         Flag = 0x00020000 // USN_REASON_COMPRESSION_CHANGE
+	EncryptionChange          Flag = 0x00040000 // USN_REASON_ENCRYPTION_CHANGE
+	ObjectIDChange            Flag = 0x00080000 // USN_REASON_OBJECT_ID_CHANGE
+	ReparsePointChange        Flag = 0x00100000 // USN_REASON_REPARSE_POINT_CHANGE
+	StreamChange              Flag = 0x00200000 // USN_REASON_STREAM_CHANGE
+	TransactedChange          Flag = 0x00400000 // USN_REASON_TRANSACTED_CHANGE
+	IntegrityChange           Flag = 0x00800000 // USN_REASON_INTEGRITY_CHANGE
+	DesiredStorageClassChange Flag = 0x01000000 // USN_REASON_DESIRED_STORAGE_CLASS_CHANGE
+	Close                     Flag = 0x80000000 // USN_REASON_CLOSE
+	KnownMask                 Flag = DataOverwrite | DataExtend | DataTruncation | NamedDataOverwrite | NamedDataExtend | NamedDataTruncation | FileCreate | FileDelete | EAChange | SecurityChange | RenameOldName | RenameNewName | IndexableChange | BasicInfoChange | HardLinkChange | CompressionChange | EncryptionChange | ObjectIDChange | ReparsePointChange | StreamChange | TransactedChange | IntegrityChange | DesiredStorageClassChange | Close
+	UnknownMask               Flag = ^KnownMask
+)
+
+// Unmarshal unmarshals the little-endian binary representation
+// of USN journal change reason flags.
+//
+// The provided data must be at least 4 bytes long, or unmarshal will
+// panic.
+func Unmarshal(data []byte) Flag {
+	return Flag(binary.LittleEndian.Uint32(data[0:4]))
+}
+
+// String returns a description of the USN journal change reason flags.
+func (f Flag) String() string {
+	var flags []string
+
+	// Report known flags
+	if f&DataOverwrite != 0 {
+		flags = append(flags, "DataOverwrite")
+	}
+	if f&DataExtend != 0 {
+		flags = append(flags, "DataExtend")
+	}
+	if f&DataTruncation != 0 {
+		flags = append(flags, "DataTruncation")
+	}
+	if f&NamedDataOverwrite != 0 {
+		flags = append(flags, "NamedDataOverwrite")
+	}
+	if f&NamedDataExtend != 0 {
+		flags = append(flags, "NamedDataExtend")
+	}
+	if f&NamedDataTruncation != 0 {
+		flags = append(flags, "NamedDataTruncation")
+	}
+	if f&FileCreate != 0 {
+		flags = append(flags, "FileCreate")
+	}
+	if f&FileDelete != 0 {
+		flags = append(flags, "FileDelete")
+	}
+	if f&EAChange != 0 {
+		flags = append(flags, "EAChange")
+	}
+	if f&SecurityChange != 0 {
+		flags = append(flags, "SecurityChange")
+	}
+	if f&RenameOldName != 0 {
+		flags = append(flags, "RenameOldName")
+	}
+	if f&RenameNewName != 0 {
+		flags = append(flags, "RenameNewName")
+	}
+	if f&IndexableChange != 0 {
+		flags = append(flags, "IndexableChange")
+	}
+	if f&BasicInfoChange != 0 {
+		flags = append(flags, "BasicInfoChange")
+	}
+	if f&HardLinkChange != 0 {
+		flags = append(flags, "HardLinkChange")
+	}
+	if f&CompressionChange != 0 {
+		flags = append(flags, "CompressionChange")
+	}
+	if f&EncryptionChange != 0 {
+		flags = append(flags, "EncryptionChange")
+	}
+	if f&ObjectIDChange != 0 {
+		flags = append(flags, "ObjectIDChange")
+	}
+	if f&ReparsePointChange != 0 {
+		flags = append(flags, "ReparsePointChange")
+	}
+	if f&StreamChange != 0 {
+		flags = append(flags, "StreamChange")
+	}
+	if f&TransactedChange != 0 {
+		flags = append(flags, "TransactedChange")
+	}
+	if f&IntegrityChange != 0 {
+		flags = append(flags, "IntegrityChange")
+	}
+	if f&DesiredStorageClassChange != 0 {
+		flags = append(flags, "DesiredStorageClassChange")
+	}
+	if f&Close != 0 {
+		flags = append(flags, "Close")
+	}
+
+	// Report unknown flags
+	if f&UnknownMask != 0 {
+		for i := uint(0); i < 32; i++ {
+			q := Flag(1) << i
+			// Find flags that are present
+			if q&f == 0 {
+				continue
+			}
+			// Skip flags that we've already identified
+			if q&UnknownMask == 0 {
+				continue
+			}
+			flags = append(flags, fmt.Sprintf("%#08x", uint32(q)))
+		}
+	}
+
+	return strings.Join(flags, ",")
+}
@@ -0,0 +1,99 @@
+package ntfs
+
+import (
+	"fmt"
+
+	"github.com/gentlemanautomaton/ntfs/attrtype"
+	"github.com/gentlemanautomaton/ntfs/volumeflag"
+)
+
+// VolumeInfo describes an NTFS volume. It combines data from the $Volume
+// system file with the serial number stored in the volume boot record.
+type VolumeInfo struct {
+	Label        string
+	VersionMajor uint8
+	VersionMinor uint8
+	Flags        volumeflag.Flag
+	SerialNumber uint64
+}
+
+// Dirty returns true if the volume is marked dirty. A dirty volume was not
+// cleanly unmounted and is scheduled for a consistency check.
+func (info *VolumeInfo) Dirty() bool {
+	return info.Flags&volumeflag.Dirty != 0
+}
+
+// UpgradeOnMount returns true if the volume is scheduled to be upgraded the
+// next time it is mounted.
+func (info *VolumeInfo) UpgradeOnMount() bool {
+	return info.Flags&volumeflag.UpgradeOnMount != 0
+}
+
+// ChkdskUnderway returns true if a consistency check was in progress when
+// the volume was last written.
+func (info *VolumeInfo) ChkdskUnderway() bool {
+	return info.Flags&volumeflag.ChkdskUnderway != 0
+}
+
+// ModifiedByChkdsk returns true if the volume has been modified by a
+// consistency check.
+func (info *VolumeInfo) ModifiedByChkdsk() bool {
+	return info.Flags&volumeflag.ModifiedByChkdsk != 0
+}
+
+// Supported returns true if the volume's NTFS version is supported by this
+// package.
+func (info *VolumeInfo) Supported() bool {
+	return info.VersionMajor == 3 && info.VersionMinor <= 1
+}
+
+// Version returns the NTFS version of the volume as a string.
+func (info *VolumeInfo) Version() string {
+	return fmt.Sprintf("%d.%d", info.VersionMajor, info.VersionMinor)
+}
+
+// String returns a description of the volume information.
+func (info *VolumeInfo) String() string {
+	output := fmt.Sprintf("\"%s\" NTFS v%s, Serial: %016X", info.Label, info.Version(), info.SerialNumber)
+	if info.Flags != 0 {
+		output += fmt.Sprintf(" (Flags: %s)", info.Flags)
+	}
+	return output
+}
+
+// VolumeInfo returns information about the volume, including its label,
+// NTFS version, flags and serial number.
+//
+// The label, version and flags are read from the $Volume system file each
+// time VolumeInfo is called. The serial number is read from the volume boot
+// record, so it is zero for standalone readers.
+func (r *Reader) VolumeInfo() (VolumeInfo, error) {
+	info := VolumeInfo{
+		SerialNumber: r.boot.VolumeSerialNumber,
+	}
+
+	file, err := r.File(RecordVolume)
+	if err != nil {
+		return info, err
+	}
+
+	if attr, ok := file.Attribute(attrtype.VolumeName); ok {
+		if info.Label, err = utf16ToString(attr.ResidentValue); err != nil {
+			return info, err
+		}
+	}
+
+	attr, ok := file.Attribute(attrtype.VolumeInformation)
+	if !ok {
+		return info, ErrVolumeInformationMissing
+	}
+	var vi VolumeInformation
+	if err := vi.UnmarshalBinary(attr.ResidentValue); err != nil {
+		return info, err
+	}
+	info.VersionMajor = vi.VersionMajor
+	info.VersionMinor = vi.VersionMinor
+	info.Flags = vi.Flags
+
+	return info, nil
+}
@@ -43,3 +43,14 @@ func (info *VolumeInformation) String() string {
 	}
 	return fmt.Sprintf("NTFS v%d.%d (Flags: %s)", info.VersionMajor, info.VersionMinor, info.Flags)
 }
+
+// MarshalBinary returns the 12-byte little-endian binary representation of
+// info.
+func (info *VolumeInformation) MarshalBinary() ([]byte, error) {
+	data := make([]byte, VolumeInformationLength)
+	binary.LittleEndian.PutUint64(data[0:8], info.reserved1)
+	data[8] = info.VersionMajor
+	data[9] = info.VersionMinor
+	binary.LittleEndian.PutUint16(data[10:12], uint16(info.Flags))
+	return data, nil
+}
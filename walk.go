@@ -0,0 +1,26 @@
+package ntfs
+
+// WalkFunc is the type of function called by Walk for each file record.
+//
+// If the record can't be read, file will be nil and err will describe the
+// problem. If the function returns a non-nil error the walk stops and Walk
+// returns that error.
+type WalkFunc func(file *File, err error) error
+
+// Walk calls fn for every file record in the master file table in order,
+// including records that are not in use.
+//
+// If the number of records in the master file table is unknown
+// ErrMFTDataUnavailable is returned.
+func (r *Reader) Walk(fn WalkFunc) error {
+	if r.records == 0 {
+		return ErrMFTDataUnavailable
+	}
+	for id := int64(0); id < r.records; id++ {
+		file, err := r.File(id)
+		if err := fn(file, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}